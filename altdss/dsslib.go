@@ -1,8 +1,23 @@
 package altdss
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/gob"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -10,6 +25,8 @@ import (
 #cgo LDFLAGS: -ldss_capi -Wl,-rpath,$ORIGIN
 #include <stdlib.h>
 #include "dss_capi_ctx.h"
+
+extern void altdssGoEventCallback(void* ctx, int32_t eventCode, int32_t step, void* ptr);
 */
 import "C"
 
@@ -171,6 +188,41 @@ const (
 	DSSJSONFlags_IncludeDefaultObjs = 256
 )
 
+// Flags used with ICircuit.SaveScript, see `Circuit_Save` (C-API) for more.
+const (
+	// Include the command CalcVoltageBases.
+	DSSSaveFlags_CalcVoltageBases = 1
+
+	// Include commands to set the voltage bases individually.
+	DSSSaveFlags_SetVoltageBases = 2
+
+	// Include most of the options (from the Set/Get DSS commands).
+	DSSSaveFlags_IncludeOptions = 4
+
+	// Include disabled circuit elements (and LoadShapes).
+	DSSSaveFlags_IncludeDisabled = 8
+
+	// Exclude default DSS items if they are not modified by the user.
+	DSSSaveFlags_ExcludeDefault = 16
+
+	// Use a single file instead of a folder for output.
+	DSSSaveFlags_SingleFile = 32
+
+	// Save the circuit elements in the order they were loaded in the active circuit.
+	// Guarantees better reproducibility, especially when the system is ill-conditioned.
+	// Requires the SingleFile flag.
+	DSSSaveFlags_KeepOrder = 64
+
+	// Do not export meter zones (as "feeders") separately. Has no effect when using a single file.
+	DSSSaveFlags_ExcludeMeterZones = 128
+
+	// Export commands to open terminals of elements.
+	DSSSaveFlags_IsOpen = 256
+
+	// Export to the result string instead of files. Requires the SingleFile flag.
+	DSSSaveFlags_ToString = 512
+)
+
 // This enum is used in the PropertyNameStyle property to control the naming convention.
 // Currently, this only affects capitalization, i.e., if you software already uses case
 // insensitive string comparisons for the property names, this is not useful. Otherwise,
@@ -188,6 +240,15 @@ const (
 	DSSPropertyNameStyle_Legacy DSSPropertyNameStyle = 2
 )
 
+// State of a phase of a fuse, as used in Fuses.Get_StateEnum/Set_StateEnum. The engine
+// reports a blown fuse phase as open.
+type FuseState int32
+
+const (
+	FuseState_Open   FuseState = 1
+	FuseState_Closed FuseState = 2
+)
+
 type GeneratorStatus int32
 
 const (
@@ -339,6 +400,14 @@ const (
 	SparseSolverOptions_AlwaysResetYPrimInvalid SparseSolverOptions = 268435456
 )
 
+type StorageStates int32
+
+const (
+	StorageStates_Charging    StorageStates = -1
+	StorageStates_Idling      StorageStates = 0
+	StorageStates_Discharging StorageStates = 1
+)
+
 type YMatrixModes int32
 
 const (
@@ -363,6 +432,83 @@ type DSSContextPtrs struct {
 	DataPtr_PInteger **int32
 	DataPtr_PByte    **uint8
 	DataPtr_PPChar   ***C.char
+
+	// When not nil, the errors from the engine are also appended here (see IDSS.CollectErrors)
+	collectedErrors *[]DSSErr
+
+	// Last style set by IDSS.Set_PropertyNameStyle, returned by Get_PropertyNameStyle since
+	// the engine has no getter for it
+	propertyNameStyle DSSPropertyNameStyle
+}
+
+// Error reported by the DSS engine, with its number and description.
+//
+// The errors from the engine returned by the API functions are of this type, so they
+// can be inspected with errors.As, e.g. to branch on the error number.
+type DSSErr struct {
+	Number      int32
+	Description string
+}
+
+func (err DSSErr) Error() string {
+	return fmt.Sprintf("(DSSError#%d) %s", err.Number, err.Description)
+}
+
+// Square complex matrix of order N. The data is stored column by column (column-major order),
+// as returned by the engine, i.e. the element at row i and column j is Data[j*N + i].
+type CMatrix struct {
+	N    int
+	Data []complex128
+}
+
+// Returns the element at row i and column j (0-based).
+func (m CMatrix) At(i int, j int) complex128 {
+	return m.Data[j*m.N+i]
+}
+
+// Wraps the column-major data of a square matrix, as returned by the engine, in a CMatrix.
+func newCMatrix(data []complex128, err error) (CMatrix, error) {
+	if err != nil {
+		return CMatrix{}, err
+	}
+	n := int(math.Round(math.Sqrt(float64(len(data)))))
+	if n*n != len(data) {
+		return CMatrix{}, fmt.Errorf("(DSSError) Got invalid data for a square matrix (%d elements).", len(data))
+	}
+	return CMatrix{n, data}, nil
+}
+
+// Square real matrix of order N, stored column by column (column-major order) like CMatrix,
+// i.e. the element at row i and column j is Data[j*N + i].
+type Matrix struct {
+	N    int
+	Data []float64
+}
+
+// Returns the element at row i and column j (0-based).
+func (m Matrix) At(i int, j int) float64 {
+	return m.Data[j*m.N+i]
+}
+
+// Value in polar form: magnitude and angle, in degrees.
+type Polar struct {
+	Mag float64
+	Ang float64
+}
+
+// Converts the flat magnitude/angle pairs returned by the engine to a slice of Polar.
+func newPolarArray(data []float64, err error) ([]Polar, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("(DSSError) Got invalid data for magnitude/angle pairs (%d elements).", len(data))
+	}
+	result := make([]Polar, len(data)/2)
+	for i := range result {
+		result[i] = Polar{data[2*i], data[2*i+1]}
+	}
+	return result, nil
 }
 
 type ICommonData struct {
@@ -375,7 +521,7 @@ type ICommonData struct {
 // type FuncGetStrings func(unsafe.Pointer, ***C.char, *C.int32_t)
 
 func (ctx *DSSContextPtrs) PrepareStringArray(value []string) **C.char {
-	data := (**C.char)(C.malloc((C.size_t)(len(value))))
+	data := (**C.char)(C.malloc((C.size_t)(len(value)) * (C.size_t)(unsafe.Sizeof(uintptr(0)))))
 	cdata := unsafe.Slice(data, len(value))
 	for i := 0; i < len(value); i++ {
 		cdata[i] = C.CString(value[i])
@@ -393,6 +539,13 @@ func (ctx *DSSContextPtrs) FreeStringArray(data **C.char, count int) {
 
 func (ctx *DSSContextPtrs) GetStringArray(data **C.char, cnt [4]int32) ([]string, error) {
 	err := ctx.DSSError()
+	if err != nil {
+		// The data may be an error sentinel, so only release it
+		if data != nil {
+			C.DSS_Dispose_PPAnsiChar(&data, (C.int32_t)(cnt[0]))
+		}
+		return nil, err
+	}
 	res_cnt := cnt[0]
 	cdata := unsafe.Slice(data, res_cnt)
 	result := make([]string, res_cnt)
@@ -412,6 +565,14 @@ func (ctx *DSSContextPtrs) GetFloat64ArrayGR() ([]float64, error) {
 	return result, err
 }
 
+// Same as GetFloat64ArrayGR, but appends the data to dst[:0], reusing its storage when large enough.
+func (ctx *DSSContextPtrs) GetFloat64ArrayGRInto(dst []float64) ([]float64, error) {
+	err := ctx.DSSError()
+	res_cnt := (*ctx.CountPtr_PDouble)[0]
+	cdata := unsafe.Slice(*ctx.DataPtr_PDouble, res_cnt)
+	return append(dst[:0], cdata...), err
+}
+
 func (ctx *DSSContextPtrs) GetComplexArrayGR() ([]complex128, error) {
 	err := ctx.DSSError()
 	res_cnt := (*ctx.CountPtr_PDouble)[0]
@@ -425,6 +586,18 @@ func (ctx *DSSContextPtrs) GetComplexArrayGR() ([]complex128, error) {
 	return result, err
 }
 
+// Same as GetComplexArrayGR, but appends the data to dst[:0], reusing its storage when large enough.
+func (ctx *DSSContextPtrs) GetComplexArrayGRInto(dst []complex128) ([]complex128, error) {
+	err := ctx.DSSError()
+	res_cnt := (*ctx.CountPtr_PDouble)[0]
+	if res_cnt == 1 {
+		res_cnt = 0
+	}
+	res_cnt /= 2
+	cdata := unsafe.Slice((*complex128)(unsafe.Pointer(*ctx.DataPtr_PDouble)), res_cnt)
+	return append(dst[:0], cdata...), err
+}
+
 func (ctx *DSSContextPtrs) GetComplexSimpleGR() (complex128, error) {
 	err := ctx.DSSError()
 	res_cnt := (*ctx.CountPtr_PDouble)[0]
@@ -445,6 +618,14 @@ func (ctx *DSSContextPtrs) GetInt32ArrayGR() ([]int32, error) {
 	return result, err
 }
 
+// Same as GetInt32ArrayGR, but appends the data to dst[:0], reusing its storage when large enough.
+func (ctx *DSSContextPtrs) GetInt32ArrayGRInto(dst []int32) ([]int32, error) {
+	err := ctx.DSSError()
+	res_cnt := (*ctx.CountPtr_PInteger)[0]
+	cdata := unsafe.Slice(*ctx.DataPtr_PInteger, res_cnt)
+	return append(dst[:0], cdata...), err
+}
+
 func (ctx *DSSContextPtrs) GetUInt8ArrayGR() ([]uint8, error) {
 	err := ctx.DSSError()
 	res_cnt := (*ctx.CountPtr_PByte)[0]
@@ -463,9 +644,18 @@ func (ctx *DSSContextPtrs) GetUInt8ArrayGR() ([]uint8, error) {
 
 func (ctx *DSSContextPtrs) DSSError() error {
 	if (*ctx.errorNumberPtr) != 0 {
-		err_result := fmt.Errorf("(DSSError#%d) %s", *ctx.errorNumberPtr, C.GoString(C.ctx_Error_Get_Description(ctx.ctxPtr)))
+		dssErr := DSSErr{*ctx.errorNumberPtr, C.GoString(C.ctx_Error_Get_Description(ctx.ctxPtr))}
 		*ctx.errorNumberPtr = 0
-		return err_result
+		if ctx.collectedErrors != nil {
+			*ctx.collectedErrors = append(*ctx.collectedErrors, dssErr)
+		}
+		return dssErr
+	}
+	if dssErr, found := takeEventPanic(ctx.ctxPtr); found {
+		if ctx.collectedErrors != nil {
+			*ctx.collectedErrors = append(*ctx.collectedErrors, dssErr)
+		}
+		return dssErr
 	}
 	return nil
 }
@@ -475,6 +665,213 @@ func (common *ICommonData) InitCommon(ctx *DSSContextPtrs) {
 	common.ctxPtr = ctx.ctxPtr
 }
 
+// Returns an error listing the names, sorted, of the objects that were not found.
+func notFoundError(what string, names []string) error {
+	sort.Strings(names)
+	return fmt.Errorf("(DSSError) %s not found: %s", what, strings.Join(names, ", "))
+}
+
+// Activates the circuit element by its full name (e.g. "Generator.g1") and returns
+// an ICktElement bound to the same context, to read the data of the element.
+func (common *ICommonData) activateCktElement(fullName string) (*ICktElement, error) {
+	fullName_c := C.CString(fullName)
+	defer C.free(unsafe.Pointer(fullName_c))
+	if C.ctx_Circuit_SetActiveElement(common.ctxPtr, fullName_c) < 0 {
+		if err := common.ctx.DSSError(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("(DSSError) Element \"%s\" not found.", fullName)
+	}
+	cktelement := &ICktElement{}
+	cktelement.Init(common.ctx)
+	return cktelement, common.ctx.DSSError()
+}
+
+// Runs fn with the circuit element `fullName` as the active circuit element, restoring the
+// previously active circuit element afterwards.
+func (common *ICommonData) withCktElement(fullName string, fn func(cktelement *ICktElement) error) error {
+	restore := common.saveActiveCktElement()
+	cktelement, err := common.activateCktElement(fullName)
+	if err != nil {
+		return err
+	}
+	err = fn(cktelement)
+	if restoreErr := restore(); err == nil {
+		err = restoreErr
+	}
+	return err
+}
+
+// Captures the active circuit element, returning a function that reactivates it. The
+// function does nothing if there was no active circuit element.
+func (common *ICommonData) saveActiveCktElement() func() error {
+	prevName := C.GoString(C.ctx_CktElement_Get_Name(common.ctxPtr))
+	if common.ctx.DSSError() != nil {
+		// No active element to restore
+		prevName = ""
+	}
+	return func() error {
+		if prevName == "" {
+			return nil
+		}
+		_, err := common.activateCktElement(prevName)
+		return err
+	}
+}
+
+// Complex power, in kVA, for each conductor of each terminal of a circuit element (by full
+// name) after the solution, preserving the active circuit element.
+func (common *ICommonData) elementPowers(fullName string) (result []complex128, err error) {
+	err = common.withCktElement(fullName, func(cktelement *ICktElement) error {
+		result, err = cktelement.Powers()
+		return err
+	})
+	return
+}
+
+// Complex currents, in amperes, for each conductor of each terminal of a circuit element (by
+// full name) after the solution, preserving the active circuit element.
+func (common *ICommonData) elementCurrents(fullName string) (result []complex128, err error) {
+	err = common.withCktElement(fullName, func(cktelement *ICktElement) error {
+		result, err = cktelement.Currents()
+		return err
+	})
+	return
+}
+
+// Total complex power, in kVA, flowing into the first terminal of a circuit element (by full
+// name) after the solution, preserving the active circuit element.
+func (common *ICommonData) elementTotalPower(fullName string) (result complex128, err error) {
+	err = common.withCktElement(fullName, func(cktelement *ICktElement) error {
+		powers, err := cktelement.TotalPowers()
+		if err != nil {
+			return err
+		}
+		if len(powers) == 0 {
+			return fmt.Errorf("(DSSError) No power data for \"%s\".", fullName)
+		}
+		result = powers[0]
+		return nil
+	})
+	return
+}
+
+// Returns the value of one of the properties of a circuit element (by full name), preserving
+// the active circuit element.
+func (common *ICommonData) getElementProperty(fullName string, propName string) (value string, err error) {
+	err = common.withCktElement(fullName, func(cktelement *ICktElement) error {
+		if err := cktelement.Properties.Set_Name(propName); err != nil {
+			return err
+		}
+		var err error
+		value, err = cktelement.Properties.Get_Val()
+		return err
+	})
+	return value, err
+}
+
+// Parses an array property value, e.g. "[1, 2, 3]", as numbers.
+func parseFloatArray(value string) ([]float64, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune("[](){}\"', \t", r)
+	})
+	result := make([]float64, len(fields))
+	for i, field := range fields {
+		var err error
+		if result[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return nil, fmt.Errorf("(DSSError) Could not parse the array: %s", value)
+		}
+	}
+	return result, nil
+}
+
+// Formats numbers as an array property value, e.g. "[1, 2, 3]".
+func formatFloatArray(values []float64) string {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return "[" + strings.Join(fields, ", ") + "]"
+}
+
+// Same as getElementProperty, parsing the value as a number.
+func (common *ICommonData) getElementPropertyFloat(fullName string, propName string) (float64, error) {
+	value, err := common.getElementProperty(fullName, propName)
+	if err != nil {
+		return 0, err
+	}
+	result, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("(DSSError) Could not parse %s of \"%s\": %s", propName, fullName, value)
+	}
+	return result, nil
+}
+
+// Sets the value of one of the properties of a circuit element (by full name), preserving
+// the active circuit element.
+func (common *ICommonData) setElementProperty(fullName string, propName string, value string) error {
+	return common.withCktElement(fullName, func(cktelement *ICktElement) error {
+		if err := cktelement.Properties.Set_Name(propName); err != nil {
+			return err
+		}
+		return cktelement.Properties.Set_Val(value)
+	})
+}
+
+// Builds a map of the register values keyed by the register names.
+func registerValuesMap(names []string, values []float64) (map[string]float64, error) {
+	if len(names) != len(values) {
+		return nil, fmt.Errorf("(DSSError) Got %d register names but %d values.", len(names), len(values))
+	}
+	result := make(map[string]float64, len(names))
+	for i, name := range names {
+		result[name] = values[i]
+	}
+	return result, nil
+}
+
+// Returns the value of the register `name` (case-insensitive) from the register names and values.
+func registerValue(names []string, values []float64, name string) (float64, error) {
+	for i := 0; i < len(names) && i < len(values); i++ {
+		if strings.EqualFold(strings.TrimSpace(names[i]), strings.TrimSpace(name)) {
+			return values[i], nil
+		}
+	}
+	return 0, fmt.Errorf("(DSSError) Register \"%s\" not found.", name)
+}
+
+// Runs the First/Next loop of a collection for the range-over-func iterators, calling
+// yield for each element until it returns false, and then calls restore. Returns the
+// first error from first, next or restore.
+func iterateCollection(first func() (int32, error), next func() (int32, error), restore func() error, yield func() bool) (err error) {
+	defer func() {
+		if restoreErr := restore(); err == nil {
+			err = restoreErr
+		}
+	}()
+	idx, err := first()
+	for ; idx != 0 && err == nil; idx, err = next() {
+		if !yield() {
+			return nil
+		}
+	}
+	return err
+}
+
+// Runs iterate for the error-yielding iterators, calling yield with a nil error for each
+// element and then once more with the error from iterate, if any. The error is dropped if
+// the loop exited early, since yield cannot be called again in that case.
+func iterateWithError(iterate func(yield func() bool) error, yield func(err error) bool) {
+	stopped := false
+	err := iterate(func() bool {
+		stopped = !yield(nil)
+		return !stopped
+	})
+	if err != nil && !stopped {
+		yield(err)
+	}
+}
+
 func (ctx *DSSContextPtrs) Init(ctxPtr unsafe.Pointer) {
 	ctx.ctxPtr = ctxPtr
 	C.ctx_DSS_Start(ctxPtr, 0)
@@ -494,6 +891,164 @@ func (ctx *DSSContextPtrs) Init(ctxPtr unsafe.Pointer) {
 	)
 }
 
+// Returns a slice backed by the engine's node voltage array (NodeV), including the
+// ground reference at index 0, i.e. the node voltages in YNodeOrder start at index 1.
+// The slice is only valid until the arrays are reallocated by the engine, e.g. in the
+// next Y matrix rebuild, and must not be retained.
+func (ctx *DSSContextPtrs) nodeVoltagesPtr() ([]complex128, error) {
+	var vptr *C.double
+	C.ctx_YMatrix_getVpointer(ctx.ctxPtr, &vptr)
+	if err := ctx.DSSError(); err != nil {
+		return nil, err
+	}
+	if vptr == nil {
+		return nil, errors.New("(DSSError) The solution arrays are not initialized.")
+	}
+	numNodes := int(C.ctx_Circuit_Get_NumNodes(ctx.ctxPtr))
+	return unsafe.Slice((*complex128)(unsafe.Pointer(vptr)), numNodes+1), ctx.DSSError()
+}
+
+// Go handler for the events of a DSS context
+type eventHandler struct {
+	ctxPtr unsafe.Pointer
+	evt    AltDSSEvent
+	fn     func(evt AltDSSEvent)
+}
+
+// Registry of the Go event handlers. The C callback is registered once per event
+// code, for all contexts, and dispatches to the handlers of the context.
+var eventHandlers = struct {
+	sync.Mutex
+	nextHandle int
+	handlers   map[int]eventHandler
+	registered map[AltDSSEvent]bool
+
+	// Panics recovered from the handlers, per context, returned by the next DSSError call
+	panics map[unsafe.Pointer]DSSErr
+}{
+	handlers:   make(map[int]eventHandler),
+	registered: make(map[AltDSSEvent]bool),
+	panics:     make(map[unsafe.Pointer]DSSErr),
+}
+
+// Number of entries in eventHandlers.panics, to check for them without locking
+var pendingEventPanics int32
+
+// Registers fn to be called when the context raises the event, returning a handle for unregisterEventHandler.
+func registerEventHandler(ctxPtr unsafe.Pointer, evt AltDSSEvent, fn func(evt AltDSSEvent)) (int, error) {
+	eventHandlers.Lock()
+	defer eventHandlers.Unlock()
+	if !eventHandlers.registered[evt] {
+		if C.DSSEvents_RegisterAlt((C.int32_t)(evt), (C.altdss_callback_event_t)(C.altdssGoEventCallback)) == 0 {
+			return 0, fmt.Errorf("(DSSError) Could not register the callback for the event %d.", evt)
+		}
+		eventHandlers.registered[evt] = true
+	}
+	eventHandlers.nextHandle++
+	eventHandlers.handlers[eventHandlers.nextHandle] = eventHandler{ctxPtr, evt, fn}
+	return eventHandlers.nextHandle, nil
+}
+
+// Removes the event handler. The C callback is unregistered when no handlers remain for the event.
+func unregisterEventHandler(handle int) error {
+	eventHandlers.Lock()
+	defer eventHandlers.Unlock()
+	handler, found := eventHandlers.handlers[handle]
+	if !found {
+		return fmt.Errorf("(DSSError) Event handler %d not found.", handle)
+	}
+	delete(eventHandlers.handlers, handle)
+	unregisterUnusedEvent(handler.evt)
+	return nil
+}
+
+// Removes all the event handlers of a context, and its pending panic, e.g. when the
+// context is disposed, so that a new context at the same address does not inherit them.
+func dropEventHandlers(ctxPtr unsafe.Pointer) {
+	eventHandlers.Lock()
+	defer eventHandlers.Unlock()
+	for handle, handler := range eventHandlers.handlers {
+		if handler.ctxPtr == ctxPtr {
+			delete(eventHandlers.handlers, handle)
+			unregisterUnusedEvent(handler.evt)
+		}
+	}
+	if _, found := eventHandlers.panics[ctxPtr]; found {
+		delete(eventHandlers.panics, ctxPtr)
+		atomic.AddInt32(&pendingEventPanics, -1)
+	}
+}
+
+// Unregisters the C callback of the event if no handlers remain for it. The caller must
+// hold the eventHandlers lock.
+func unregisterUnusedEvent(evt AltDSSEvent) {
+	for _, other := range eventHandlers.handlers {
+		if other.evt == evt {
+			return
+		}
+	}
+	if eventHandlers.registered[evt] {
+		C.DSSEvents_UnregisterAlt((C.int32_t)(evt), (C.altdss_callback_event_t)(C.altdssGoEventCallback))
+		delete(eventHandlers.registered, evt)
+	}
+}
+
+//export altdssGoEventCallback
+func altdssGoEventCallback(ctxPtr unsafe.Pointer, eventCode C.int32_t, step C.int32_t, ptr unsafe.Pointer) {
+	evt := AltDSSEvent(eventCode)
+	// Copy the handlers, so they can be (un)registered from the handlers themselves
+	eventHandlers.Lock()
+	handles := make([]int, 0, len(eventHandlers.handlers))
+	for handle, handler := range eventHandlers.handlers {
+		if handler.ctxPtr == ctxPtr && handler.evt == evt {
+			handles = append(handles, handle)
+		}
+	}
+	sort.Ints(handles)
+	fns := make([]func(AltDSSEvent), len(handles))
+	for i, handle := range handles {
+		fns[i] = eventHandlers.handlers[handle].fn
+	}
+	eventHandlers.Unlock()
+	for _, fn := range fns {
+		callEventHandler(ctxPtr, fn, evt)
+	}
+}
+
+// Runs an event handler, recovering from panics, which must not unwind through the engine.
+// The first panic is kept for the context and returned as an error by the next API call.
+func callEventHandler(ctxPtr unsafe.Pointer, fn func(AltDSSEvent), evt AltDSSEvent) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		eventHandlers.Lock()
+		defer eventHandlers.Unlock()
+		if _, found := eventHandlers.panics[ctxPtr]; found {
+			return
+		}
+		eventHandlers.panics[ctxPtr] = DSSErr{0, fmt.Sprintf("Panic in the handler of the event %d: %v", evt, r)}
+		atomic.AddInt32(&pendingEventPanics, 1)
+	}()
+	fn(evt)
+}
+
+// Returns and clears the panic recovered from an event handler of the context, if any.
+func takeEventPanic(ctxPtr unsafe.Pointer) (DSSErr, bool) {
+	if atomic.LoadInt32(&pendingEventPanics) == 0 {
+		return DSSErr{}, false
+	}
+	eventHandlers.Lock()
+	defer eventHandlers.Unlock()
+	dssErr, found := eventHandlers.panics[ctxPtr]
+	if found {
+		delete(eventHandlers.panics, ctxPtr)
+		atomic.AddInt32(&pendingEventPanics, -1)
+	}
+	return dssErr, found
+}
+
 func ToUint16(v bool) C.uint16_t {
 	if v {
 		return (C.uint16_t)(1)
@@ -619,6 +1174,9 @@ func (bus *IBus) TotalMiles() (float64, error) {
 }
 
 // For 2- and 3-phase buses, returns array of complex numbers represetin L-L voltages in volts. Returns -1.0 for 1-phase bus. If more than 3 phases, returns only first 3.
+//
+// For 3-phase buses, the values are ordered as AB, BC, CA, following the node order of the bus.
+// See LineToLineVoltages for a labeled version.
 func (bus *IBus) VLL() ([]complex128, error) {
 	C.ctx_Bus_Get_VLL_GR(bus.ctxPtr)
 	return bus.ctx.GetComplexArrayGR()
@@ -630,6 +1188,13 @@ func (bus *IBus) VMagAngle() ([]float64, error) {
 	return bus.ctx.GetFloat64ArrayGR()
 }
 
+// Same as VMagAngle, as magnitude (VLN) and angle (degrees) pairs.
+//
+// (API Extension)
+func (bus *IBus) VPolar() ([]Polar, error) {
+	return newPolarArray(bus.VMagAngle())
+}
+
 // Open circuit voltage; Complex array.
 func (bus *IBus) Voc() ([]complex128, error) {
 	C.ctx_Bus_Get_Voc_GR(bus.ctxPtr)
@@ -642,12 +1207,69 @@ func (bus *IBus) Voltages() ([]complex128, error) {
 	return bus.ctx.GetComplexArrayGR()
 }
 
+// Writes the complex voltages of the nodes of this bus, in the same order as Voltages
+// (see Nodes), directly into the node voltage array of the solution. The length of `value`
+// must match NumNodes.
+//
+// This is intended for custom solvers and co-simulation, e.g. to seed the voltages before
+// SolveDirect. The values only last until the next solution overwrites the node voltages.
+//
+// (API Extension)
+func (bus *IBus) Set_Voltages(value []complex128) error {
+	numNodes, err := bus.NumNodes()
+	if err != nil {
+		return err
+	}
+	if len(value) != int(numNodes) {
+		return fmt.Errorf("(DSSError) Invalid number of voltages for the bus: got %d, expected %d.", len(value), numNodes)
+	}
+	name, err := bus.Name()
+	if err != nil {
+		return err
+	}
+	nodes, err := bus.Nodes()
+	if err != nil {
+		return err
+	}
+	var cnt [4]int32
+	var data **C.char
+	C.ctx_Circuit_Get_YNodeOrder(bus.ctxPtr, &data, (*C.int32_t)(&cnt[0]))
+	nodeOrder, err := bus.ctx.GetStringArray(data, cnt)
+	if err != nil {
+		return err
+	}
+	// The Y matrix order matches the node voltage array, which starts with the ground node
+	nodeRefs := make(map[string]int, len(nodeOrder))
+	for i, nodeName := range nodeOrder {
+		nodeRefs[strings.ToLower(nodeName)] = i + 1
+	}
+	nodeV, err := bus.ctx.nodeVoltagesPtr()
+	if err != nil {
+		return err
+	}
+	for i, node := range nodes {
+		ref, found := nodeRefs[strings.ToLower(fmt.Sprintf("%s.%d", name, node))]
+		if !found || ref >= len(nodeV) {
+			return fmt.Errorf("(DSSError) Node \"%s.%d\" not found in the system.", name, node)
+		}
+		nodeV[ref] = value[i]
+	}
+	return nil
+}
+
 // Complex array of Ysc matrix at bus. Column by column.
 func (bus *IBus) YscMatrix() ([]complex128, error) {
 	C.ctx_Bus_Get_YscMatrix_GR(bus.ctxPtr)
 	return bus.ctx.GetComplexArrayGR()
 }
 
+// Same as YscMatrix, as a CMatrix.
+//
+// (API Extension)
+func (bus *IBus) YscMatrixM() (CMatrix, error) {
+	return newCMatrix(bus.YscMatrix())
+}
+
 // Complex Zero-Sequence short circuit impedance at bus.
 func (bus *IBus) Zsc0() (complex128, error) {
 	C.ctx_Bus_Get_Zsc0_GR(bus.ctxPtr)
@@ -666,6 +1288,13 @@ func (bus *IBus) ZscMatrix() ([]complex128, error) {
 	return bus.ctx.GetComplexArrayGR()
 }
 
+// Same as ZscMatrix, as a CMatrix.
+//
+// (API Extension)
+func (bus *IBus) ZscMatrixM() (CMatrix, error) {
+	return newCMatrix(bus.ZscMatrix())
+}
+
 // Base voltage at bus in kV
 func (bus *IBus) Get_kVBase() (float64, error) {
 	return (float64)(C.ctx_Bus_Get_kVBase(bus.ctxPtr)), bus.ctx.DSSError()
@@ -677,12 +1306,57 @@ func (bus *IBus) PUVLL() ([]complex128, error) {
 	return bus.ctx.GetComplexArrayGR()
 }
 
+// Returns the L-L voltages (in volts) of the active bus keyed by the phase pair, e.g. "AB", "BC" and "CA"
+// for a 3-phase bus. The labels are derived from the node numbers of the bus (1=A, 2=B, 3=C), so a
+// 2-phase bus with nodes 1 and 3 results in a single "AC" entry.
+//
+// Returns an error for 1-phase buses, which have no L-L voltages.
+//
+// (API Extension)
+func (bus *IBus) LineToLineVoltages() (map[string]complex128, error) {
+	vll, err := bus.VLL()
+	if err != nil {
+		return nil, err
+	}
+	if len(vll) == 0 {
+		return nil, errors.New("(DSSError) L-L voltages are not available for 1-phase buses.")
+	}
+	nodes, err := bus.Nodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) > 3 {
+		nodes = nodes[:3]
+	}
+	result := make(map[string]complex128, len(vll))
+	for i := 0; i < len(vll) && i < len(nodes); i++ {
+		label := phaseLabel(nodes[i]) + phaseLabel(nodes[(i+1)%len(nodes)])
+		result[label] = vll[i]
+	}
+	return result, nil
+}
+
+// Returns the conventional phase letter for node numbers 1 to 3, or the node number itself otherwise.
+func phaseLabel(node int32) string {
+	if node >= 1 && node <= 3 {
+		return string(rune('A' + node - 1))
+	}
+	return fmt.Sprint(node)
+}
+
 // Array of doubles containing voltage magnitude, angle (degrees) pairs in per unit
 func (bus *IBus) PUVMagAngle() ([]float64, error) {
 	C.ctx_Bus_Get_puVmagAngle_GR(bus.ctxPtr)
 	return bus.ctx.GetFloat64ArrayGR()
 }
 
+// Same as PUVMagAngle, as magnitude (pu) and angle (degrees) pairs.
+//
+// (API Extension)
+func (bus *IBus) PUVPolar() ([]Polar, error) {
+	return newPolarArray(bus.PUVMagAngle())
+}
+
 // Complex Array of pu voltages at the bus.
 func (bus *IBus) PUVoltages() ([]complex128, error) {
 	C.ctx_Bus_Get_puVoltages_GR(bus.ctxPtr)
@@ -1073,7 +1747,9 @@ func (capacitors *ICapacitors) Set_kV(value float64) error {
 	return capacitors.ctx.DSSError()
 }
 
-// Total bank KVAR, distributed equally among phases and steps.
+// Total bank KVAR, i.e. the sum of the kvar of all steps, distributed equally among phases.
+// Setting it distributes the value equally among the steps; use Set_kvarPerStep for banks
+// with unequal steps.
 func (capacitors *ICapacitors) Get_kvar() (float64, error) {
 	return (float64)(C.ctx_Capacitors_Get_kvar(capacitors.ctxPtr)), capacitors.ctx.DSSError()
 }
@@ -1083,6 +1759,42 @@ func (capacitors *ICapacitors) Set_kvar(value float64) error {
 	return capacitors.ctx.DSSError()
 }
 
+// kvar rating of each step of the active capacitor bank.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (capacitors *ICapacitors) Get_kvarPerStep() ([]float64, error) {
+	name, err := capacitors.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	value, err := capacitors.getElementProperty("Capacitor."+name, "kvar")
+	if err != nil {
+		return nil, err
+	}
+	return parseFloatArray(value)
+}
+
+// Sets the kvar rating of each step of the active capacitor bank; the number of steps
+// (NumSteps) is set to the number of values. The steps do not need to be equal.
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (capacitors *ICapacitors) Set_kvarPerStep(value []float64) error {
+	if len(value) == 0 {
+		return errors.New("(DSSError) At least one step is required.")
+	}
+	name, err := capacitors.Get_Name()
+	if err != nil {
+		return err
+	}
+	if err = capacitors.setElementProperty("Capacitor."+name, "NumSteps", strconv.Itoa(len(value))); err != nil {
+		return err
+	}
+	return capacitors.setElementProperty("Capacitor."+name, "kvar", formatFloatArray(value))
+}
+
 type ICktElement struct {
 	ICommonData
 
@@ -1150,6 +1862,38 @@ func (cktelement *ICktElement) Open(Term int32, Phs int32) error {
 	return cktelement.ctx.DSSError()
 }
 
+// Opens all phases of all terminals of the active element, isolating it completely.
+//
+// (API Extension)
+func (cktelement *ICktElement) OpenAll() error {
+	numTerminals, err := cktelement.NumTerminals()
+	if err != nil {
+		return err
+	}
+	for term := int32(1); term <= numTerminals; term++ {
+		if err = cktelement.Open(term, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closes all phases of all terminals of the active element.
+//
+// (API Extension)
+func (cktelement *ICktElement) CloseAll() error {
+	numTerminals, err := cktelement.NumTerminals()
+	if err != nil {
+		return err
+	}
+	for term := int32(1); term <= numTerminals; term++ {
+		if err = cktelement.Close(term, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Array containing all property names of the active device.
 func (cktelement *ICktElement) AllPropertyNames() ([]string, error) {
 	var cnt [4]int32
@@ -1174,6 +1918,29 @@ func (cktelement *ICktElement) AllVariableValues() ([]float64, error) {
 	return cktelement.ctx.GetFloat64ArrayGR()
 }
 
+// Values of the state variables of the active element, keyed by variable name (see
+// AllVariableNames and AllVariableValues). Valid only for PCElements.
+//
+// (API Extension)
+func (cktelement *ICktElement) VariablesMap() (map[string]float64, error) {
+	names, err := cktelement.AllVariableNames()
+	if err != nil {
+		return nil, err
+	}
+	values, err := cktelement.AllVariableValues()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(names) {
+		return nil, errors.New("(DSSError) The state variable values do not match the variable names.")
+	}
+	result := make(map[string]float64, len(names))
+	for i, name := range names {
+		result[name] = values[i]
+	}
+	return result, nil
+}
+
 // Array of strings. Get  Bus definitions to which each terminal is connected.
 func (cktelement *ICktElement) Get_BusNames() ([]string, error) {
 	var cnt [4]int32
@@ -1213,6 +1980,13 @@ func (cktelement *ICktElement) CurrentsMagAng() ([]float64, error) {
 	return cktelement.ctx.GetFloat64ArrayGR()
 }
 
+// Same as CurrentsMagAng, as magnitude and angle (degrees) pairs.
+//
+// (API Extension)
+func (cktelement *ICktElement) CurrentsPolar() ([]Polar, error) {
+	return newPolarArray(cktelement.CurrentsMagAng())
+}
+
 // Display name of the object (not necessarily unique)
 func (cktelement *ICktElement) Get_DisplayName() (string, error) {
 	return C.GoString(C.ctx_CktElement_Get_DisplayName(cktelement.ctxPtr)), cktelement.ctx.DSSError()
@@ -1380,18 +2154,112 @@ func (cktelement *ICktElement) Voltages() ([]complex128, error) {
 	return cktelement.ctx.GetComplexArrayGR()
 }
 
+// Voltage drop across a two-terminal element (e.g. a line), per phase: the voltages of
+// terminal 1 minus the voltages of terminal 2, in volts.
+//
+// (API Extension)
+func (cktelement *ICktElement) VoltageDrop() ([]complex128, error) {
+	numTerminals, err := cktelement.NumTerminals()
+	if err != nil {
+		return nil, err
+	}
+	if numTerminals != 2 {
+		return nil, errors.New("(DSSError) Voltage drop is only available for two-terminal elements.")
+	}
+	numConductors, err := cktelement.NumConductors()
+	if err != nil {
+		return nil, err
+	}
+	numPhases, err := cktelement.NumPhases()
+	if err != nil {
+		return nil, err
+	}
+	volts, err := cktelement.Voltages()
+	if err != nil {
+		return nil, err
+	}
+	if int32(len(volts)) < 2*numConductors || numPhases > numConductors {
+		return nil, errors.New("(DSSError) Got invalid voltage data for the active element.")
+	}
+	result := make([]complex128, numPhases)
+	for i := int32(0); i < numPhases; i++ {
+		result[i] = volts[i] - volts[numConductors+i]
+	}
+	return result, nil
+}
+
+// Sets multiple properties of the active circuit element, keyed by property name. The
+// properties are set in name order, since map iteration order is undefined in Go.
+//
+// All properties are attempted; the error, if any, lists each property that failed.
+//
+// (API Extension)
+func (cktelement *ICktElement) SetProperties(kv map[string]string) error {
+	names := make([]string, 0, len(kv))
+	for name := range kv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var failed []string
+	for _, name := range names {
+		err := cktelement.Properties.Set_Name(name)
+		if err == nil {
+			err = cktelement.Properties.Set_Val(kv[name])
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", name, err))
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("(DSSError) Could not set the properties: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Returns the values of the properties of the active circuit element, keyed by property name.
+//
+// (API Extension)
+func (cktelement *ICktElement) GetProperties(names []string) (map[string]string, error) {
+	result := make(map[string]string, len(names))
+	for _, name := range names {
+		if err := cktelement.Properties.Set_Name(name); err != nil {
+			return nil, err
+		}
+		value, err := cktelement.Properties.Get_Val()
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
 // Voltages at each conductor in magnitude, angle form as array of doubles.
 func (cktelement *ICktElement) VoltagesMagAng() ([]float64, error) {
 	C.ctx_CktElement_Get_VoltagesMagAng_GR(cktelement.ctxPtr)
 	return cktelement.ctx.GetFloat64ArrayGR()
 }
 
+// Same as VoltagesMagAng, as magnitude and angle (degrees) pairs.
+//
+// (API Extension)
+func (cktelement *ICktElement) VoltagesPolar() ([]Polar, error) {
+	return newPolarArray(cktelement.VoltagesMagAng())
+}
+
 // YPrim matrix, column order, complex numbers
 func (cktelement *ICktElement) Yprim() ([]complex128, error) {
 	C.ctx_CktElement_Get_Yprim_GR(cktelement.ctxPtr)
 	return cktelement.ctx.GetComplexArrayGR()
 }
 
+// Same as Yprim, as a CMatrix.
+//
+// (API Extension)
+func (cktelement *ICktElement) YprimM() (CMatrix, error) {
+	return newCMatrix(cktelement.Yprim())
+}
+
 // Returns true if the current active element is isolated.
 // Note that this only fetches the current value. See also the Topology interface.
 //
@@ -1468,12 +2336,64 @@ func (generators *IGenerators) Set_idx(value int32) error {
 	return generators.ctx.DSSError()
 }
 
-// Indicates whether the generator is forced ON regardles of other dispatch criteria.
-func (generators *IGenerators) Get_ForcedON() (bool, error) {
-	return (C.ctx_Generators_Get_ForcedON(generators.ctxPtr) != 0), generators.ctx.DSSError()
+// Iterator over the generators, positioned on each generator in turn, for use with
+// range-over-func (Go 1.23+; the type is equivalent to `iter.Seq[*IGenerators]`):
+//
+//	for elem := range circuit.Generators.All() { ... }
+//
+// Disabled elements are included according to the IterateDisabled setting. The iteration
+// stops at the first error; use AllWithError to get it. The previously active generator is
+// restored at the end of the loop, including when the loop exits early.
+//
+// (API Extension)
+func (generators *IGenerators) All() func(yield func(*IGenerators) bool) {
+	return func(yield func(*IGenerators) bool) {
+		generators.iterate(func() bool {
+			return yield(generators)
+		})
+	}
 }
 
-func (generators *IGenerators) Set_ForcedON(value bool) error {
+// Same as All, also yielding the errors (the type is equivalent to
+// `iter.Seq2[*IGenerators, error]`):
+//
+//	for elem, err := range circuit.Generators.AllWithError() { ... }
+//
+// If iterating or restoring the previously active generator fails, the last pair yielded is
+// (nil, err).
+//
+// (API Extension)
+func (generators *IGenerators) AllWithError() func(yield func(*IGenerators, error) bool) {
+	return func(yield func(*IGenerators, error) bool) {
+		iterateWithError(generators.iterate, func(err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(generators, nil)
+		})
+	}
+}
+
+// Runs the First/Next loop for All and AllWithError, restoring the previously active generator.
+func (generators *IGenerators) iterate(yield func() bool) error {
+	prevIdx, err := generators.Get_idx()
+	if err != nil {
+		return err
+	}
+	return iterateCollection(generators.First, generators.Next, func() error {
+		if prevIdx > 0 {
+			return generators.Set_idx(prevIdx)
+		}
+		return nil
+	}, yield)
+}
+
+// Indicates whether the generator is forced ON regardles of other dispatch criteria.
+func (generators *IGenerators) Get_ForcedON() (bool, error) {
+	return (C.ctx_Generators_Get_ForcedON(generators.ctxPtr) != 0), generators.ctx.DSSError()
+}
+
+func (generators *IGenerators) Set_ForcedON(value bool) error {
 	C.ctx_Generators_Set_ForcedON(generators.ctxPtr, ToUint16(value))
 	return generators.ctx.DSSError()
 }
@@ -1522,6 +2442,36 @@ func (generators *IGenerators) RegisterValues() ([]float64, error) {
 	return generators.ctx.GetFloat64ArrayGR()
 }
 
+// Register values of the active generator, keyed by the register names (see RegisterNames).
+//
+// (API Extension)
+func (generators *IGenerators) RegisterValuesMap() (map[string]float64, error) {
+	names, err := generators.RegisterNames()
+	if err != nil {
+		return nil, err
+	}
+	values, err := generators.RegisterValues()
+	if err != nil {
+		return nil, err
+	}
+	return registerValuesMap(names, values)
+}
+
+// Value of a single register of the active generator, by name (case-insensitive), e.g. "kWh".
+//
+// (API Extension)
+func (generators *IGenerators) Register(name string) (float64, error) {
+	names, err := generators.RegisterNames()
+	if err != nil {
+		return 0, err
+	}
+	values, err := generators.RegisterValues()
+	if err != nil {
+		return 0, err
+	}
+	return registerValue(names, values, name)
+}
+
 // Vmaxpu for generator model
 func (generators *IGenerators) Get_Vmaxpu() (float64, error) {
 	return (float64)(C.ctx_Generators_Get_Vmaxpu(generators.ctxPtr)), generators.ctx.DSSError()
@@ -1572,6 +2522,123 @@ func (generators *IGenerators) Set_kW(value float64) error {
 	return generators.ctx.DSSError()
 }
 
+// kW output of all generators, in index order (1..Count). Each generator is activated in
+// turn; the active generator is preserved, also on errors.
+//
+// (API Extension)
+func (generators *IGenerators) Get_kWAll() (result []float64, err error) {
+	count, err := generators.Count()
+	if err != nil {
+		return nil, err
+	}
+	restore, err := generators.saveIdx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if restoreErr := restore(); err == nil && restoreErr != nil {
+			result, err = nil, restoreErr
+		}
+	}()
+	result = make([]float64, count)
+	for i := range result {
+		if err = generators.Set_idx(int32(i + 1)); err != nil {
+			return nil, err
+		}
+		if result[i], err = generators.Get_kW(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Sets the kW output of all generators, in index order (1..Count). This is a convenience
+// over iterating the generators in Go; it still activates each generator in turn, i.e. it
+// does not reduce the number of calls to the engine.
+// The length of `values` must match Count. The active generator is preserved, also on errors.
+//
+// (API Extension)
+func (generators *IGenerators) Set_kWAll(values []float64) (err error) {
+	count, err := generators.Count()
+	if err != nil {
+		return err
+	}
+	if len(values) != int(count) {
+		return fmt.Errorf("(DSSError) Invalid number of values: got %d, expected %d generators.", len(values), count)
+	}
+	restore, err := generators.saveIdx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := restore(); err == nil {
+			err = restoreErr
+		}
+	}()
+	for i, value := range values {
+		if err = generators.Set_idx(int32(i + 1)); err != nil {
+			return err
+		}
+		if err = generators.Set_kW(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Captures the active generator index, returning a function that reactivates it.
+func (generators *IGenerators) saveIdx() (func() error, error) {
+	prevIdx, err := generators.Get_idx()
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		if prevIdx > 0 {
+			return generators.Set_idx(prevIdx)
+		}
+		return nil
+	}, nil
+}
+
+// Complex power, in kVA, for each conductor of the active generator after the solution, as
+// flowing into the terminal, i.e. the power produced is negative. The active circuit
+// element is preserved.
+//
+// (API Extension)
+func (generators *IGenerators) Powers() ([]complex128, error) {
+	name, err := generators.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return generators.elementPowers("Generator." + name)
+}
+
+// Complex currents, in amperes, for each conductor of the active generator after the
+// solution. The active circuit element is preserved.
+//
+// (API Extension)
+func (generators *IGenerators) Currents() ([]complex128, error) {
+	name, err := generators.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return generators.elementCurrents("Generator." + name)
+}
+
+// Total complex power, in kVA, of the active generator after the solution, as flowing into
+// the terminal (negative when generating). This differs from the dispatch setpoints (Get_kW,
+// Get_kvar) when the generator model or its limits change the output. The active circuit
+// element is preserved.
+//
+// (API Extension)
+func (generators *IGenerators) TotalPower() (complex128, error) {
+	name, err := generators.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return generators.elementTotalPower("Generator." + name)
+}
+
 // kvar output for the active generator. Updates power factor based on present kW value.
 func (generators *IGenerators) Get_kvar() (float64, error) {
 	return (float64)(C.ctx_Generators_Get_kvar(generators.ctxPtr)), generators.ctx.DSSError()
@@ -1688,6 +2755,19 @@ func (generators *IGenerators) Set_Bus1(value string) error {
 	return generators.ctx.DSSError()
 }
 
+// Actual complex power output of the active generator after the solution, in kVA,
+// i.e. the total power out of its terminal. This differs from the setpoint (Get_kW,
+// Get_kvar) in modes where the output varies, e.g. with loadshapes or limits.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (generators *IGenerators) OutputPower() (complex128, error) {
+	power, err := generators.TotalPower()
+	// Powers are reported as flowing into the terminal
+	return -power, err
+}
+
 type ILines struct {
 	ICommonData
 }
@@ -1744,6 +2824,89 @@ func (lines *ILines) Set_idx(value int32) error {
 	return lines.ctx.DSSError()
 }
 
+// Iterator over the lines, positioned on each line in turn, for use with
+// range-over-func (Go 1.23+; the type is equivalent to `iter.Seq[*ILines]`):
+//
+//	for elem := range circuit.Lines.All() { ... }
+//
+// Disabled elements are included according to the IterateDisabled setting. The iteration
+// stops at the first error; use AllWithError to get it. The previously active line is
+// restored at the end of the loop, including when the loop exits early.
+//
+// (API Extension)
+func (lines *ILines) All() func(yield func(*ILines) bool) {
+	return func(yield func(*ILines) bool) {
+		lines.iterate(func() bool {
+			return yield(lines)
+		})
+	}
+}
+
+// Same as All, also yielding the errors (the type is equivalent to
+// `iter.Seq2[*ILines, error]`):
+//
+//	for elem, err := range circuit.Lines.AllWithError() { ... }
+//
+// If iterating or restoring the previously active line fails, the last pair yielded is
+// (nil, err).
+//
+// (API Extension)
+func (lines *ILines) AllWithError() func(yield func(*ILines, error) bool) {
+	return func(yield func(*ILines, error) bool) {
+		iterateWithError(lines.iterate, func(err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(lines, nil)
+		})
+	}
+}
+
+// Runs the First/Next loop for All and AllWithError, restoring the previously active line.
+func (lines *ILines) iterate(yield func() bool) error {
+	prevIdx, err := lines.Get_idx()
+	if err != nil {
+		return err
+	}
+	return iterateCollection(lines.First, lines.Next, func() error {
+		if prevIdx > 0 {
+			return lines.Set_idx(prevIdx)
+		}
+		return nil
+	}, yield)
+}
+
+// Total powers (complex, kVA) at each terminal of the active line, as flowing into the terminals.
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (lines *ILines) TotalPowers() (result []complex128, err error) {
+	name, err := lines.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	err = lines.withCktElement("Line."+name, func(cktelement *ICktElement) error {
+		result, err = cktelement.TotalPowers()
+		return err
+	})
+	return
+}
+
+// Total losses (complex, in VA) of the active line. The active circuit element is preserved.
+//
+// (API Extension)
+func (lines *ILines) Losses() (result complex128, err error) {
+	name, err := lines.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	err = lines.withCktElement("Line."+name, func(cktelement *ICktElement) error {
+		result, err = cktelement.Losses()
+		return err
+	})
+	return
+}
+
 func (lines *ILines) New(Name string) (int32, error) {
 	Name_c := C.CString(Name)
 	defer C.free(unsafe.Pointer(Name_c))
@@ -2012,6 +3175,64 @@ func (lines *ILines) SeasonRating() (float64, error) {
 	return (float64)(C.ctx_Lines_Get_SeasonRating(lines.ctxPtr)), lines.ctx.DSSError()
 }
 
+// Ampacity ratings of the active line, one per season (see SeasonRating), in Amps.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (lines *ILines) Get_Ratings() ([]float64, error) {
+	name, err := lines.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	value, err := lines.getElementProperty("Line."+name, "Ratings")
+	if err != nil {
+		return nil, err
+	}
+	return parseFloatArray(value)
+}
+
+// Sets the ampacity ratings of the active line; the number of ratings (NumAmpRatings) is
+// set to the number of values. The active circuit element is preserved.
+//
+// (API Extension)
+func (lines *ILines) Set_Ratings(value []float64) error {
+	if len(value) == 0 {
+		return errors.New("(DSSError) At least one rating is required.")
+	}
+	name, err := lines.Get_Name()
+	if err != nil {
+		return err
+	}
+	if err = lines.setElementProperty("Line."+name, "Seasons", strconv.Itoa(len(value))); err != nil {
+		return err
+	}
+	return lines.setElementProperty("Line."+name, "Ratings", formatFloatArray(value))
+}
+
+// Number of ampacity ratings of the active line (the "Seasons" property). Set it before
+// editing the ratings individually; Set_Ratings already sets it.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (lines *ILines) Get_NumAmpRatings() (int32, error) {
+	name, err := lines.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	value, err := lines.getElementPropertyFloat("Line."+name, "Seasons")
+	return int32(value), err
+}
+
+func (lines *ILines) Set_NumAmpRatings(value int32) error {
+	name, err := lines.Get_Name()
+	if err != nil {
+		return err
+	}
+	return lines.setElementProperty("Line."+name, "Seasons", strconv.Itoa(int(value)))
+}
+
 // Sets/gets the Line element switch status. Setting it has side-effects to the line parameters.
 //
 // (API Extension)
@@ -2045,6 +3266,28 @@ func (settings *ISettings) Set_AllowDuplicates(value bool) error {
 	return settings.ctx.DSSError()
 }
 
+// Runs fn with AllowDuplicates set to value, restoring the previous setting afterwards,
+// even if fn fails or panics. Use it to limit the scope of the option to the block that
+// intentionally creates objects with duplicate names, since the option has performance
+// impacts while enabled (see Get_AllowDuplicates).
+//
+// (API Extension)
+func (settings *ISettings) WithAllowDuplicates(value bool, fn func() error) (err error) {
+	previous, err := settings.Get_AllowDuplicates()
+	if err != nil {
+		return err
+	}
+	if err = settings.Set_AllowDuplicates(value); err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := settings.Set_AllowDuplicates(previous); err == nil {
+			err = restoreErr
+		}
+	}()
+	return fn()
+}
+
 // List of Buses or (File=xxxx) syntax for the AutoAdd solution mode.
 func (settings *ISettings) Get_AutoBusList() (string, error) {
 	return C.GoString(C.ctx_Settings_Get_AutoBusList(settings.ctxPtr)), settings.ctx.DSSError()
@@ -2212,6 +3455,51 @@ func (settings *ISettings) Set_ZoneLock(value bool) error {
 	return settings.ctx.DSSError()
 }
 
+// Allocation factor of the loads. Setting it sets the AllocationFactor of all loads.
+//
+// The C-API has no direct getter, so the value is read from the loads. This is a getter for
+// the uniform case only, e.g. to round-trip the value set here: an error is returned if there
+// are no loads or if the loads have different allocation factors, which is the normal state
+// after an allocation (e.g. "AllocateLoads"). Use ILoads.Get_AllocationFactor to read the
+// factor of each load. The active load is preserved, also on errors.
+//
+// (API Extension)
+func (settings *ISettings) Get_AllocationFactors() (result float64, err error) {
+	loads := ILoads{}
+	loads.Init(settings.ctx)
+	prevIdx, err := loads.Get_idx()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if prevIdx <= 0 {
+			return
+		}
+		if restoreErr := loads.Set_idx(prevIdx); err == nil && restoreErr != nil {
+			result, err = 0, restoreErr
+		}
+	}()
+	found := false
+	idx, err := loads.First()
+	for ; idx != 0 && err == nil; idx, err = loads.Next() {
+		value, err := loads.Get_AllocationFactor()
+		if err != nil {
+			return 0, err
+		}
+		if found && value != result {
+			return 0, errors.New("(DSSError) The loads have different allocation factors.")
+		}
+		result, found = value, true
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, errors.New("(DSSError) There are no loads in the active circuit.")
+	}
+	return result, nil
+}
+
 func (settings *ISettings) Set_AllocationFactors(value float64) error {
 	C.ctx_Settings_Set_AllocationFactors(settings.ctxPtr, (C.double)(value))
 	return settings.ctx.DSSError()
@@ -2439,6 +3727,18 @@ func (capcontrols *ICapControls) Set_Mode(value int32) error {
 	return capcontrols.ctx.DSSError()
 }
 
+// Same as Get_Mode, typed with the CapControlModes enumeration.
+//
+// (API Extension)
+func (capcontrols *ICapControls) Get_ModeEnum() (CapControlModes, error) {
+	value, err := capcontrols.Get_Mode()
+	return (CapControlModes)(value), err
+}
+
+func (capcontrols *ICapControls) Set_ModeEnum(value CapControlModes) error {
+	return capcontrols.Set_Mode((int32)(value))
+}
+
 // Full name of the element that PT and CT are connected to.
 func (capcontrols *ICapControls) Get_MonitoredObj() (string, error) {
 	return C.GoString(C.ctx_CapControls_Get_MonitoredObj(capcontrols.ctxPtr)), capcontrols.ctx.DSSError()
@@ -2652,6 +3952,175 @@ func (circuit *ICircuit) Capacity(Start float64, Increment float64) (float64, er
 	return (float64)(C.ctx_Circuit_Capacity(circuit.ctxPtr, (C.double)(Start), (C.double)(Increment))), circuit.ctx.DSSError()
 }
 
+// Runs the same capacity sweep as Capacity and returns a structured report:
+//
+//   - capacityFactor: the load multiplier returned by Capacity;
+//   - addedMW: the increase in power delivered to the circuit, in MW, from the
+//     present load multiplier to capacityFactor;
+//   - violatingElement: the full name of the PD element with the highest loading
+//     (above 100% of its normal rating) at the first step past capacityFactor.
+//     An empty string means that no element was overloaded, i.e. the capacity was
+//     limited by voltage.
+//
+// The load multiplier is restored and the circuit is solved again before returning.
+//
+// (API Extension)
+func (circuit *ICircuit) CapacityReport(Start float64, Increment float64) (capacityFactor float64, addedMW float64, violatingElement string, err error) {
+	solution := &circuit.Solution
+	loadMult, err := solution.Get_LoadMult()
+	if err != nil {
+		return
+	}
+	defer func() {
+		restoreErr := solution.Set_LoadMult(loadMult)
+		if restoreErr == nil {
+			restoreErr = solution.SolveSnap()
+		}
+		if err == nil {
+			err = restoreErr
+		}
+	}()
+	if err = solution.SolveSnap(); err != nil {
+		return
+	}
+	basePower, err := circuit.TotalPower()
+	if err != nil {
+		return
+	}
+	if capacityFactor, err = circuit.Capacity(Start, Increment); err != nil {
+		return
+	}
+	if err = solution.Set_LoadMult(capacityFactor); err != nil {
+		return
+	}
+	if err = solution.SolveSnap(); err != nil {
+		return
+	}
+	capPower, err := circuit.TotalPower()
+	if err != nil {
+		return
+	}
+	// TotalPower is negative when the circuit is importing power from the sources
+	addedMW = -(real(capPower) - real(basePower)) / 1000
+	if err = solution.Set_LoadMult(capacityFactor + Increment); err != nil {
+		return
+	}
+	if err = solution.SolveSnap(); err != nil {
+		return
+	}
+	names, err := circuit.PDElements.AllNames()
+	if err != nil {
+		return
+	}
+	pctNorm, err := circuit.PDElements.AllPctNorm(false)
+	if err != nil {
+		return
+	}
+	maxPct := 100.0
+	for i := 0; i < len(names) && i < len(pctNorm); i++ {
+		if pctNorm[i] > maxPct {
+			maxPct = pctNorm[i]
+			violatingElement = names[i]
+		}
+	}
+	return
+}
+
+// Summary of a snapshot solution, as returned by ICircuit.QuickSolve
+type SolveReport struct {
+	Converged      bool
+	TotalPower     complex128 // Total power delivered to the circuit, in kVA (see ICircuit.TotalPower)
+	Losses         complex128 // Total losses, in VA (see ICircuit.Losses)
+	MinVoltagePU   float64    // Lowest node voltage magnitude, in per unit
+	MinVoltageNode string     // Name of the node with the lowest voltage
+	MaxVoltagePU   float64    // Highest node voltage magnitude, in per unit
+	MaxVoltageNode string     // Name of the node with the highest voltage
+}
+
+// Runs a snapshot solution and returns a summary of the results.
+// If the solution does not converge, the report is still returned with Converged set to false.
+//
+// (API Extension)
+func (circuit *ICircuit) QuickSolve() (SolveReport, error) {
+	var report SolveReport
+	err := circuit.Solution.SolveSnap()
+	if err != nil {
+		return report, err
+	}
+	if report.Converged, err = circuit.Solution.Get_Converged(); err != nil {
+		return report, err
+	}
+	if report.TotalPower, err = circuit.TotalPower(); err != nil {
+		return report, err
+	}
+	if report.Losses, err = circuit.Losses(); err != nil {
+		return report, err
+	}
+	names, err := circuit.AllNodeNames()
+	if err != nil {
+		return report, err
+	}
+	vmagpu, err := circuit.AllBusVmagPu()
+	if err != nil {
+		return report, err
+	}
+	for i := 0; i < len(names) && i < len(vmagpu); i++ {
+		if i == 0 || vmagpu[i] < report.MinVoltagePU {
+			report.MinVoltagePU = vmagpu[i]
+			report.MinVoltageNode = names[i]
+		}
+		if i == 0 || vmagpu[i] > report.MaxVoltagePU {
+			report.MaxVoltagePU = vmagpu[i]
+			report.MaxVoltageNode = names[i]
+		}
+	}
+	return report, nil
+}
+
+// Selects the rating season used by the PD elements (see ILines.SeasonRating) for the whole simulation.
+//
+// This enables the "SeasonalRating" option and points the "SeasonSignal" option to an XYCurve
+// named "altdss_season_signal", created or updated here with the constant value `season`.
+// The season is the (zero-based) index into the ratings array of each element; elements with
+// fewer ratings use their normal rating.
+//
+// Note that the curve is a regular DSS object of the active circuit: it is listed by
+// XYCurves (AllNames, Count) and included when saving the circuit. The engine cannot delete
+// DSS objects other than circuit elements, so the curve remains until the circuit is cleared;
+// use ClearSeason to disable the seasonal ratings again.
+//
+// (API Extension)
+func (circuit *ICircuit) SetSeason(season int32) error {
+	const curveName = "altdss_season_signal"
+	names, err := circuit.XYCurves.AllNames()
+	if err != nil {
+		return err
+	}
+	verb := "new"
+	for _, name := range names {
+		if strings.EqualFold(name, curveName) {
+			verb = "edit"
+			break
+		}
+	}
+	text := IText{}
+	text.Init(circuit.ctx)
+	return text.Commands([]string{
+		fmt.Sprintf("%s XYCurve.%s npts=2 xarray=[0 8760] yarray=[%d %d]", verb, curveName, season, season),
+		fmt.Sprintf("set SeasonalRating=yes SeasonSignal=%s", curveName),
+	})
+}
+
+// Disables the seasonal ratings selected by SetSeason, i.e. the PD elements use their normal
+// ratings again. The "altdss_season_signal" XYCurve is not removed, see SetSeason.
+//
+// (API Extension)
+func (circuit *ICircuit) ClearSeason() error {
+	text := IText{}
+	text.Init(circuit.ctx)
+	return text.Set_Command("set SeasonalRating=no")
+}
+
 func (circuit *ICircuit) Disable(Name string) error {
 	Name_c := C.CString(Name)
 	C.ctx_Circuit_Disable(circuit.ctxPtr, Name_c)
@@ -2737,6 +4206,21 @@ func (circuit *ICircuit) SetActiveBus(BusName string) (int32, error) {
 	return (int32)(C.ctx_Circuit_SetActiveBus(circuit.ctxPtr, BusName_c)), circuit.ctx.DSSError()
 }
 
+// Activates a bus by name and returns the ActiveBus interface positioned on it. Returns an
+// error if the bus does not exist, instead of leaving no active bus as SetActiveBus does.
+//
+// (API Extension)
+func (circuit *ICircuit) ActivateBus(name string) (*IBus, error) {
+	idx, err := circuit.SetActiveBus(name)
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("(DSSError) Bus \"%s\" not found.", name)
+	}
+	return &circuit.ActiveBus, nil
+}
+
 func (circuit *ICircuit) SetActiveBusi(BusIndex int32) (int32, error) {
 	return (int32)(C.ctx_Circuit_SetActiveBusi(circuit.ctxPtr, (C.int32_t)(BusIndex))), circuit.ctx.DSSError()
 }
@@ -2747,6 +4231,33 @@ func (circuit *ICircuit) SetActiveClass(ClassName string) (int32, error) {
 	return (int32)(C.ctx_Circuit_SetActiveClass(circuit.ctxPtr, ClassName_c)), circuit.ctx.DSSError()
 }
 
+// Activates a DSS class by name (e.g. "Capacitor") and returns the ActiveClass interface
+// positioned on it. Returns an error if the class does not exist.
+//
+// (API Extension)
+func (circuit *ICircuit) ActivateClass(name string) (*IActiveClass, error) {
+	idx, err := circuit.SetActiveClass(name)
+	if err != nil {
+		return nil, err
+	}
+	if idx <= 0 {
+		return nil, fmt.Errorf("(DSSError) Class \"%s\" not found.", name)
+	}
+	return &circuit.ActiveClass, nil
+}
+
+// Activates a circuit element by its full name (e.g. "Line.l1") and returns the
+// ActiveCktElement interface positioned on it. Returns an error if the element does not
+// exist, instead of leaving the previous element active as SetActiveElement may.
+//
+// (API Extension)
+func (circuit *ICircuit) ActivateElement(fullName string) (*ICktElement, error) {
+	if _, err := circuit.activateCktElement(fullName); err != nil {
+		return nil, err
+	}
+	return &circuit.ActiveCktElement, nil
+}
+
 func (circuit *ICircuit) SetActiveElement(FullName string) (int32, error) {
 	FullName_c := C.CString(FullName)
 	defer C.free(unsafe.Pointer(FullName_c))
@@ -2784,23 +4295,187 @@ func (circuit *ICircuit) AllBusVmagPu() ([]float64, error) {
 	return circuit.ctx.GetFloat64ArrayGR()
 }
 
+// Node voltages from the most recent solution, in volts, keyed by node name (see AllNodeNames).
+//
+// (API Extension)
+func (circuit *ICircuit) AllNodeVoltagesMap() (map[string]complex128, error) {
+	names, err := circuit.AllNodeNames()
+	if err != nil {
+		return nil, err
+	}
+	volts, err := circuit.AllBusVolts()
+	if err != nil {
+		return nil, err
+	}
+	if len(volts) != len(names) {
+		return nil, errors.New("(DSSError) The node voltages do not match the node names.")
+	}
+	result := make(map[string]complex128, len(names))
+	for i, name := range names {
+		result[name] = volts[i]
+	}
+	return result, nil
+}
+
+// Node voltage magnitudes from the most recent solution, in per unit, keyed by node name (see AllNodeNames).
+//
+// (API Extension)
+func (circuit *ICircuit) AllBusVmagPuMap() (map[string]float64, error) {
+	names, err := circuit.AllNodeNames()
+	if err != nil {
+		return nil, err
+	}
+	vmagpu, err := circuit.AllBusVmagPu()
+	if err != nil {
+		return nil, err
+	}
+	if len(vmagpu) != len(names) {
+		return nil, errors.New("(DSSError) The node voltages do not match the node names.")
+	}
+	result := make(map[string]float64, len(names))
+	for i, name := range names {
+		result[name] = vmagpu[i]
+	}
+	return result, nil
+}
+
+// Same as AllBusVmagPu, but reuses the storage of dst when large enough, avoiding an allocation
+// per call in time-series loops. Returns the resulting slice, as in `append`.
+//
+// (API Extension)
+func (circuit *ICircuit) AllBusVmagPuInto(dst []float64) ([]float64, error) {
+	C.ctx_Circuit_Get_AllBusVmagPu_GR(circuit.ctxPtr)
+	return circuit.ctx.GetFloat64ArrayGRInto(dst)
+}
+
+// Summary statistics of the node voltage magnitudes in per unit (see AllBusVmagPu):
+// minimum, maximum, mean and (population) standard deviation.
+//
+// (API Extension)
+func (circuit *ICircuit) VoltageSummary() (vmin, vmax, mean, stddev float64, err error) {
+	vmagpu, err := circuit.AllBusVmagPu()
+	if err != nil {
+		return
+	}
+	if len(vmagpu) == 0 {
+		err = errors.New("(DSSError) No voltages available.")
+		return
+	}
+	vmin, vmax = vmagpu[0], vmagpu[0]
+	for _, v := range vmagpu {
+		mean += v
+		vmin = math.Min(vmin, v)
+		vmax = math.Max(vmax, v)
+	}
+	mean /= float64(len(vmagpu))
+	for _, v := range vmagpu {
+		stddev += (v - mean) * (v - mean)
+	}
+	stddev = math.Sqrt(stddev / float64(len(vmagpu)))
+	return
+}
+
 // Complex array of all bus, node voltages from most recent solution
 func (circuit *ICircuit) AllBusVolts() ([]complex128, error) {
 	C.ctx_Circuit_Get_AllBusVolts_GR(circuit.ctxPtr)
 	return circuit.ctx.GetComplexArrayGR()
 }
 
+// Returns the sequence voltages (zero, positive and negative, in this order) of each bus with the
+// nodes 1, 2 and 3, keyed by bus name. The sequence components are computed from the node voltages
+// of the present solution, in volts. Buses without the three phase nodes are not included.
+//
+// (API Extension)
+func (circuit *ICircuit) SequenceVoltages() (map[string][3]complex128, error) {
+	names, err := circuit.AllNodeNames()
+	if err != nil {
+		return nil, err
+	}
+	volts, err := circuit.AllBusVolts()
+	if err != nil {
+		return nil, err
+	}
+	phaseVolts := make(map[string]*[3]complex128)
+	phaseCount := make(map[string]int)
+	for i := 0; i < len(names) && i < len(volts); i++ {
+		sep := strings.LastIndex(names[i], ".")
+		if sep < 0 {
+			continue
+		}
+		node, err := strconv.Atoi(names[i][sep+1:])
+		if err != nil || node < 1 || node > 3 {
+			continue
+		}
+		busName := names[i][:sep]
+		if phaseVolts[busName] == nil {
+			phaseVolts[busName] = &[3]complex128{}
+		}
+		phaseVolts[busName][node-1] = volts[i]
+		phaseCount[busName]++
+	}
+	result := make(map[string][3]complex128)
+	for busName, v := range phaseVolts {
+		if phaseCount[busName] == 3 {
+			result[busName] = phaseToSequence(*v)
+		}
+	}
+	return result, nil
+}
+
+// Converts phase quantities (A, B, C) to sequence quantities (zero, positive, negative)
+func phaseToSequence(abc [3]complex128) [3]complex128 {
+	a := cmplx.Rect(1, 2*math.Pi/3)
+	a2 := a * a
+	return [3]complex128{
+		(abc[0] + abc[1] + abc[2]) / 3,
+		(abc[0] + a*abc[1] + a2*abc[2]) / 3,
+		(abc[0] + a2*abc[1] + a*abc[2]) / 3,
+	}
+}
+
 // Array of total losses (complex) in each circuit element
 func (circuit *ICircuit) AllElementLosses() ([]complex128, error) {
 	C.ctx_Circuit_Get_AllElementLosses_GR(circuit.ctxPtr)
 	return circuit.ctx.GetComplexArrayGR()
 }
 
-// Array of strings containing Full Name of all elements.
-func (circuit *ICircuit) AllElementNames() ([]string, error) {
-	var cnt [4]int32
-	var data **C.char
-	C.ctx_Circuit_Get_AllElementNames(circuit.ctxPtr, &data, (*C.int32_t)(&cnt[0]))
+// Complex power, in kVA, for each conductor of each terminal of every circuit element after
+// the solution, keyed by the full element name (see AllElementNames). Useful to compare the
+// power flows of two scenarios. Each element is activated in turn to read its powers; the
+// active circuit element is preserved.
+//
+// (API Extension)
+func (circuit *ICircuit) AllElementPowers() (result map[string][]complex128, err error) {
+	names, err := circuit.AllElementNames()
+	if err != nil {
+		return nil, err
+	}
+	restore := circuit.saveActiveCktElement()
+	defer func() {
+		if restoreErr := restore(); err == nil && restoreErr != nil {
+			result, err = nil, restoreErr
+		}
+	}()
+	result = make(map[string][]complex128, len(names))
+	for i, name := range names {
+		cktelement, err := circuit.get_CktElementsi(int32(i))
+		if err != nil {
+			return nil, err
+		}
+		powers, err := cktelement.Powers()
+		if err != nil {
+			return nil, err
+		}
+		result[name] = powers
+	}
+	return result, nil
+}
+
+// Array of strings containing Full Name of all elements.
+func (circuit *ICircuit) AllElementNames() ([]string, error) {
+	var cnt [4]int32
+	var data **C.char
+	C.ctx_Circuit_Get_AllElementNames(circuit.ctxPtr, &data, (*C.int32_t)(&cnt[0]))
 	return circuit.ctx.GetStringArray(data, cnt)
 }
 
@@ -2908,8 +4583,265 @@ func (circuit *ICircuit) ToJSON(options int32) (string, error) {
 	return C.GoString(C.ctx_Circuit_ToJSON(circuit.ctxPtr, (C.int32_t)(options))), circuit.ctx.DSSError()
 }
 
+// Captures the active bus, returning a function that reactivates it. The function does
+// nothing if there was no active bus.
+func (circuit *ICircuit) saveActiveBus() func() error {
+	prevName, err := circuit.ActiveBus.Name()
+	if err != nil {
+		// No active bus to restore
+		prevName = ""
+	}
+	return func() error {
+		if prevName == "" {
+			return nil
+		}
+		_, err := circuit.SetActiveBus(prevName)
+		return err
+	}
+}
+
+// Sets the coordinates of the buses, where x[i] and y[i] are the coordinates of the bus names[i].
+// The buses are activated by index instead of by name. Unknown buses are skipped, and returned
+// in the error after setting the coordinates of the others.
+//
+// The active bus is preserved.
+//
+// (API Extension)
+func (circuit *ICircuit) SetBusCoords(names []string, x []float64, y []float64) (err error) {
+	if len(x) != len(names) || len(y) != len(names) {
+		return fmt.Errorf("(DSSError) Invalid number of coordinates: got %d names, %d x values and %d y values.", len(names), len(x), len(y))
+	}
+	restore := circuit.saveActiveBus()
+	defer func() {
+		if restoreErr := restore(); err == nil {
+			err = restoreErr
+		}
+	}()
+	allNames, err := circuit.AllBusNames()
+	if err != nil {
+		return err
+	}
+	busIndices := make(map[string]int32, len(allNames))
+	for i, name := range allNames {
+		busIndices[strings.ToLower(name)] = int32(i)
+	}
+	var missing []string
+	bus := &circuit.ActiveBus
+	for i, name := range names {
+		busIndex, found := busIndices[strings.ToLower(name)]
+		if !found {
+			missing = append(missing, name)
+			continue
+		}
+		if _, err = circuit.SetActiveBusi(busIndex); err != nil {
+			return err
+		}
+		if err = bus.Set_x(x[i]); err != nil {
+			return err
+		}
+		if err = bus.Set_y(y[i]); err != nil {
+			return err
+		}
+	}
+	if len(missing) != 0 {
+		return notFoundError("Buses", missing)
+	}
+	return nil
+}
+
+// Returns the names and coordinates of the buses with defined coordinates.
+//
+// The active bus is preserved.
+//
+// (API Extension)
+func (circuit *ICircuit) GetBusCoords() (names []string, x []float64, y []float64, err error) {
+	restore := circuit.saveActiveBus()
+	defer func() {
+		if restoreErr := restore(); err == nil && restoreErr != nil {
+			names, x, y, err = nil, nil, nil, restoreErr
+		}
+	}()
+	allNames, err := circuit.AllBusNames()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bus := &circuit.ActiveBus
+	for i, name := range allNames {
+		var defined bool
+		var busX, busY float64
+		if _, err = circuit.SetActiveBusi(int32(i)); err != nil {
+			return nil, nil, nil, err
+		}
+		if defined, err = bus.Coorddefined(); err != nil {
+			return nil, nil, nil, err
+		}
+		if !defined {
+			continue
+		}
+		if busX, err = bus.Get_x(); err != nil {
+			return nil, nil, nil, err
+		}
+		if busY, err = bus.Get_y(); err != nil {
+			return nil, nil, nil, err
+		}
+		names = append(names, name)
+		x = append(x, busX)
+		y = append(y, busY)
+	}
+	return names, x, y, nil
+}
+
+// Writes the script produced by "save circuit" for the active circuit to `w`, as a single
+// script. The script is generated in memory by the engine (Circuit_Save with the
+// DSSSaveFlags_SingleFile and DSSSaveFlags_ToString flags), so no files are written.
+//
+// The `options` parameter contains bit-flags from DSSSaveFlags, e.g.
+// DSSSaveFlags_CalcVoltageBases to include the "CalcVoltageBases" command in the script.
+// The SingleFile and ToString flags are always added.
+//
+// (API Extension)
+func (circuit *ICircuit) SaveScript(w io.Writer, options int32) error {
+	flags := uint32(options) | DSSSaveFlags_SingleFile | DSSSaveFlags_ToString
+	empty_c := C.CString("")
+	script := C.GoString(C.ctx_Circuit_Save(circuit.ctxPtr, empty_c, (C.uint32_t)(flags)))
+	C.free(unsafe.Pointer(empty_c))
+	if err := circuit.ctx.DSSError(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// Property edit for ICircuit.ApplyEdits
+type Edit struct {
+	// Full name of the element, e.g. "Load.671"
+	Element string
+	// Property name
+	Property string
+	// New value for the property
+	Value string
+}
+
+// Applies the property edits in order and returns a function that restores the original values,
+// which are captured before applying each edit. If one of the edits fails, the edits applied so far
+// are rolled back and the error is returned; if the rollback fails too, the returned error wraps the
+// original error and includes the rollback error.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (circuit *ICircuit) ApplyEdits(edits []Edit) (rollback func() error, err error) {
+	originals := make([]Edit, 0, len(edits))
+	rollback = func() error {
+		for i := len(originals) - 1; i >= 0; i-- {
+			edit := originals[i]
+			if err := circuit.setElementProperty(edit.Element, edit.Property, edit.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, edit := range edits {
+		value, err := circuit.getElementProperty(edit.Element, edit.Property)
+		if err == nil {
+			err = circuit.setElementProperty(edit.Element, edit.Property, edit.Value)
+		}
+		if err != nil {
+			if rollbackErr := rollback(); rollbackErr != nil {
+				return nil, fmt.Errorf("%w (rolling back the previous edits also failed: %v)", err, rollbackErr)
+			}
+			return nil, err
+		}
+		originals = append(originals, Edit{edit.Element, edit.Property, value})
+	}
+	return rollback, nil
+}
+
+// Total rated reactive compensation, in Mvar, connected in the circuit: capacitive
+// from the closed steps of the capacitor banks, and inductive from the shunt
+// reactors (reactors without a distinct second bus). Disabled elements are skipped.
+//
+// The active capacitor, reactor and circuit element are preserved, also on errors.
+//
+// (API Extension)
+func (circuit *ICircuit) ShuntCompensation() (capacitiveMvar, inductiveMvar float64, err error) {
+	capacitors := &circuit.Capacitors
+	reactors := &circuit.Reactors
+	restoreElement := circuit.saveActiveCktElement()
+	prevCapacitorIdx, err := capacitors.Get_idx()
+	if err != nil {
+		return
+	}
+	prevReactorIdx, err := reactors.Get_idx()
+	if err != nil {
+		return
+	}
+	defer func() {
+		var restoreErr error
+		if prevCapacitorIdx > 0 {
+			restoreErr = capacitors.Set_idx(prevCapacitorIdx)
+		}
+		if prevReactorIdx > 0 && restoreErr == nil {
+			restoreErr = reactors.Set_idx(prevReactorIdx)
+		}
+		// The circuit element last, since activating a capacitor or reactor changes it
+		if restoreErr == nil {
+			restoreErr = restoreElement()
+		}
+		if err == nil && restoreErr != nil {
+			capacitiveMvar, inductiveMvar, err = 0, 0, restoreErr
+		}
+	}()
+
+	idx, err := capacitors.First()
+	for ; idx != 0 && err == nil; idx, err = capacitors.Next() {
+		var kvar []float64
+		var states []int32
+		if kvar, err = capacitors.Get_kvarPerStep(); err != nil {
+			return
+		}
+		if states, err = capacitors.Get_States(); err != nil {
+			return
+		}
+		for i := 0; i < len(states) && i < len(kvar); i++ {
+			if states[i] != 0 {
+				capacitiveMvar += kvar[i] / 1000
+			}
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	idx, err = reactors.First()
+	for ; idx != 0 && err == nil; idx, err = reactors.Next() {
+		var bus1, bus2 string
+		var kvar float64
+		if bus1, err = reactors.Get_Bus1(); err != nil {
+			return
+		}
+		if bus2, err = reactors.Get_Bus2(); err != nil {
+			return
+		}
+		bus1 = strings.SplitN(bus1, ".", 2)[0]
+		bus2 = strings.SplitN(bus2, ".", 2)[0]
+		if bus2 != "" && !strings.EqualFold(bus1, bus2) {
+			// Series reactor
+			continue
+		}
+		if kvar, err = reactors.Get_kvar(); err != nil {
+			return
+		}
+		inductiveMvar += kvar / 1000
+	}
+	return
+}
+
 type ICtrlQueue struct {
 	ICommonData
+
+	// Handles of the event handlers registered by SetCallback
+	callbackHandles []int
 }
 
 func (ctrlqueue *ICtrlQueue) Init(ctx *DSSContextPtrs) {
@@ -2984,6 +4916,65 @@ func (ctrlqueue *ICtrlQueue) Set_Action(value int32) error {
 	return ctrlqueue.ctx.DSSError()
 }
 
+// Sets a Go callback to implement user-defined controls, replacing the previous one; use nil to
+// remove it. The callback is called by the engine during the control iterations of the solution
+// for the legacy control events: AltDSSEvent_Legacy_InitControls, AltDSSEvent_Legacy_CheckControls
+// and AltDSSEvent_Legacy_StepControls.
+//
+// The actions pushed with a user-defined device handle (see Push) are delivered to the action
+// list when due. From the callback, use PopActions to process them (reading the action code and
+// the device handle of each one), and Push to schedule new actions.
+// Panics in the callback are recovered and reported as in IDSS.RegisterEventHandler.
+//
+// (API Extension)
+func (ctrlqueue *ICtrlQueue) SetCallback(fn func(evt AltDSSEvent)) error {
+	for _, handle := range ctrlqueue.callbackHandles {
+		if err := unregisterEventHandler(handle); err != nil {
+			return err
+		}
+	}
+	ctrlqueue.callbackHandles = nil
+	if fn == nil {
+		return nil
+	}
+	for _, evt := range []AltDSSEvent{AltDSSEvent_Legacy_InitControls, AltDSSEvent_Legacy_CheckControls, AltDSSEvent_Legacy_StepControls} {
+		handle, err := registerEventHandler(ctrlqueue.ctxPtr, evt, fn)
+		if err != nil {
+			ctrlqueue.SetCallback(nil)
+			return err
+		}
+		ctrlqueue.callbackHandles = append(ctrlqueue.callbackHandles, handle)
+	}
+	return nil
+}
+
+// Pops all the actions from the action list, calling fn with the action code and the device
+// handle of each one. Stops at the first error, including errors returned by fn.
+//
+// (API Extension)
+func (ctrlqueue *ICtrlQueue) PopActions(fn func(actionCode int32, deviceHandle int32) error) error {
+	for {
+		remaining, err := ctrlqueue.PopAction()
+		if err != nil {
+			return err
+		}
+		if remaining == 0 {
+			return nil
+		}
+		actionCode, err := ctrlqueue.ActionCode()
+		if err != nil {
+			return err
+		}
+		deviceHandle, err := ctrlqueue.DeviceHandle()
+		if err != nil {
+			return err
+		}
+		if err = fn(actionCode, deviceHandle); err != nil {
+			return err
+		}
+	}
+}
+
 type IDSSElement struct {
 	ICommonData
 
@@ -3023,6 +5014,31 @@ func (dsselement *IDSSElement) ToJSON(options int32) (string, error) {
 	return C.GoString(C.ctx_DSSElement_ToJSON(dsselement.ctxPtr, (C.int32_t)(options))), dsselement.ctx.DSSError()
 }
 
+// Value of a property of the active DSS object, by its (zero-based) index, as in
+// Properties.Set_idx followed by Properties.Get_Val. This is a convenience only: the
+// C-API has no single call for it, so it still makes both calls, and the active property
+// of the Properties interface is changed to the one read.
+//
+// (API Extension)
+func (dsselement *IDSSElement) PropValByIdx(idx int32) (string, error) {
+	if err := dsselement.Properties.Set_idx(idx); err != nil {
+		return "", err
+	}
+	return dsselement.Properties.Get_Val()
+}
+
+// Sets the value of a property of the active DSS object, by its (zero-based) index, as in
+// Properties.Set_idx followed by Properties.Set_Val. As with PropValByIdx, this still makes
+// both calls, and the active property of the Properties interface is changed to the one set.
+//
+// (API Extension)
+func (dsselement *IDSSElement) SetPropValByIdx(idx int32, value string) error {
+	if err := dsselement.Properties.Set_idx(idx); err != nil {
+		return err
+	}
+	return dsselement.Properties.Set_Val(value)
+}
+
 type IDSSProgress struct {
 	ICommonData
 }
@@ -3389,6 +5405,58 @@ func (fuses *IFuses) Set_NormalState(value []string) error {
 	return fuses.ctx.DSSError()
 }
 
+// State of each phase of the active fuse, as FuseState values (see Get_State).
+//
+// (API Extension)
+func (fuses *IFuses) Get_StateEnum() ([]FuseState, error) {
+	states, err := fuses.Get_State()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]FuseState, len(states))
+	for i, state := range states {
+		switch strings.ToLower(strings.TrimSpace(state)) {
+		case "open":
+			result[i] = FuseState_Open
+		case "closed":
+			result[i] = FuseState_Closed
+		default:
+			return nil, fmt.Errorf("(DSSError) Unknown fuse state: \"%s\".", state)
+		}
+	}
+	return result, nil
+}
+
+func (fuses *IFuses) Set_StateEnum(value []FuseState) error {
+	states := make([]string, len(value))
+	for i, state := range value {
+		switch state {
+		case FuseState_Open:
+			states[i] = "open"
+		case FuseState_Closed:
+			states[i] = "closed"
+		default:
+			return fmt.Errorf("(DSSError) Invalid fuse state: %d.", state)
+		}
+	}
+	return fuses.Set_State(states)
+}
+
+// Returns true if the fuse of the given phase (1-based) of the active fuse is blown, i.e.
+// open. A phase opened manually (see Open) is also reported as blown.
+//
+// (API Extension)
+func (fuses *IFuses) IsBlownPhase(phase int32) (bool, error) {
+	states, err := fuses.Get_StateEnum()
+	if err != nil {
+		return false, err
+	}
+	if phase < 1 || int(phase) > len(states) {
+		return false, fmt.Errorf("(DSSError) Invalid phase number: %d (the fuse has %d phases).", phase, len(states))
+	}
+	return states[phase-1] == FuseState_Open, nil
+}
+
 type IISources struct {
 	ICommonData
 }
@@ -3628,6 +5696,46 @@ func (linecodes *ILineCodes) Set_Rmatrix(value []float64) error {
 	return linecodes.ctx.DSSError()
 }
 
+// Wraps a matrix of the active line code, sized by its number of phases, in a Matrix.
+func (linecodes *ILineCodes) phaseMatrix(data []float64, err error) (Matrix, error) {
+	if err != nil {
+		return Matrix{}, err
+	}
+	phases, err := linecodes.Get_Phases()
+	if err != nil {
+		return Matrix{}, err
+	}
+	n := int(phases)
+	if n*n != len(data) {
+		return Matrix{}, fmt.Errorf("(DSSError) Got %d elements for a matrix of order %d.", len(data), n)
+	}
+	return Matrix{n, data}, nil
+}
+
+// Same as Get_Rmatrix, as a Matrix of order Phases. The matrix is symmetric, so the row
+// and column order are interchangeable.
+//
+// (API Extension)
+func (linecodes *ILineCodes) RmatrixM() (Matrix, error) {
+	return linecodes.phaseMatrix(linecodes.Get_Rmatrix())
+}
+
+// Same as Get_Xmatrix, as a Matrix of order Phases. The matrix is symmetric, so the row
+// and column order are interchangeable.
+//
+// (API Extension)
+func (linecodes *ILineCodes) XmatrixM() (Matrix, error) {
+	return linecodes.phaseMatrix(linecodes.Get_Xmatrix())
+}
+
+// Same as Get_Cmatrix, as a Matrix of order Phases. The matrix is symmetric, so the row
+// and column order are interchangeable.
+//
+// (API Extension)
+func (linecodes *ILineCodes) CmatrixM() (Matrix, error) {
+	return linecodes.phaseMatrix(linecodes.Get_Cmatrix())
+}
+
 func (linecodes *ILineCodes) Get_Units() (LineUnits, error) {
 	return (LineUnits)(C.ctx_LineCodes_Get_Units(linecodes.ctxPtr)), linecodes.ctx.DSSError()
 }
@@ -3668,6 +5776,28 @@ func (linecodes *ILineCodes) Set_Xmatrix(value []float64) error {
 	return linecodes.ctx.DSSError()
 }
 
+// Creates a new LineCode named `newName` as a copy of the active LineCode, including
+// impedances, units, phases, ratings and matrices. The new LineCode becomes the active one,
+// so it can be modified without affecting the original.
+//
+// (API Extension)
+func (linecodes *ILineCodes) CloneTo(newName string) error {
+	name, err := linecodes.Get_Name()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return errors.New("(DSSError) No active LineCode to clone.")
+	}
+	text := IText{}
+	text.Init(linecodes.ctx)
+	err = text.Set_Command(fmt.Sprintf("new LineCode.%s like=%s", newName, name))
+	if err != nil {
+		return err
+	}
+	return linecodes.Set_Name(newName)
+}
+
 type IMonitors struct {
 	ICommonData
 }
@@ -3676,7 +5806,125 @@ func (monitors *IMonitors) Init(ctx *DSSContextPtrs) {
 	monitors.InitCommon(ctx)
 }
 
-// TODO: Implement AsMatrix someday
+// Size of the header of the monitor ByteStream: signature, version, record size and mode
+// (int32 each), followed by the 256-byte channel header string.
+const monitorStreamHeaderSize = 272
+
+// Signature expected at the start of the monitor ByteStream.
+const monitorStreamSignature = 43756
+
+// Returns the samples of the active monitor as a matrix, one row per sample, with the columns
+// [hour, second, channel 1, channel 2, ...]. The data is decoded from a single ByteStream call.
+// If the stream is still empty, Save is called first.
+//
+// (API Extension)
+func (monitors *IMonitors) AsMatrix() ([][]float64, error) {
+	result, _, err := monitors.decodeByteStream()
+	return result, err
+}
+
+// Returns the data of all channels of the active monitor, one slice per channel, and the
+// channel names from the Header, in the same order. Unlike calling Channel for each channel,
+// the data is decoded from a single ByteStream call. If the stream is still empty, Save is
+// called first.
+//
+// (API Extension)
+func (monitors *IMonitors) AllChannels() ([][]float64, []string, error) {
+	matrix, header, err := monitors.decodeByteStream()
+	if err != nil {
+		return nil, nil, err
+	}
+	channels := make([][]float64, len(header))
+	for i := range channels {
+		channel := make([]float64, len(matrix))
+		for j, row := range matrix {
+			// Skip the hour and second columns
+			channel[j] = row[i+2]
+		}
+		channels[i] = channel
+	}
+	return channels, header, nil
+}
+
+// Writes the samples of the active monitor to `w` as CSV, without using the engine's file
+// output: a header row with "hour", "t(sec)" and the channel names from Header, followed by
+// one row per sample, as decoded by AsMatrix. If the stream is still empty, Save is called
+// first.
+//
+// (API Extension)
+func (monitors *IMonitors) WriteCSV(w io.Writer) error {
+	matrix, header, err := monitors.decodeByteStream()
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	record := make([]string, 0, len(header)+2)
+	record = append(record, "hour", "t(sec)")
+	for _, name := range header {
+		record = append(record, strings.TrimSpace(name))
+	}
+	if err = writer.Write(record); err != nil {
+		return err
+	}
+	for _, row := range matrix {
+		record = record[:0]
+		for _, value := range row {
+			// The samples are stored as float32 in the stream
+			record = append(record, strconv.FormatFloat(value, 'g', -1, 32))
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Decodes the ByteStream of the active monitor into rows of [hour, second, channels...],
+// validating it against the channel names from the Header, which are also returned.
+func (monitors *IMonitors) decodeByteStream() ([][]float64, []string, error) {
+	stream, err := monitors.ByteStream()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stream) <= monitorStreamHeaderSize {
+		if err = monitors.Save(); err != nil {
+			return nil, nil, err
+		}
+		if stream, err = monitors.ByteStream(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(stream) < monitorStreamHeaderSize {
+		return nil, nil, errors.New("(DSSError) Monitor stream is empty.")
+	}
+	if int32(binary.LittleEndian.Uint32(stream[0:4])) != monitorStreamSignature {
+		return nil, nil, errors.New("(DSSError) Invalid monitor stream signature.")
+	}
+	recordSize, err := monitors.RecordSize()
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := monitors.Header()
+	if err != nil {
+		return nil, nil, err
+	}
+	if int(recordSize) != len(header) || int32(binary.LittleEndian.Uint32(stream[8:12])) != recordSize {
+		return nil, nil, fmt.Errorf("(DSSError) Monitor record size (%d) does not match the header (%d channels).", recordSize, len(header))
+	}
+	numColumns := int(recordSize) + 2
+	data := stream[monitorStreamHeaderSize:]
+	numRows := len(data) / (4 * numColumns)
+	result := make([][]float64, numRows)
+	for i := range result {
+		row := make([]float64, numColumns)
+		for j := range row {
+			row[j] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*(i*numColumns+j):])))
+		}
+		result[i] = row
+	}
+	return result, header, nil
+}
 
 // Array of float64 for the specified channel (usage: MyArray = DSSMonitor.Channel(i)).
 // A Save or SaveAll should be executed first. Done automatically by most standard solution modes.
@@ -3816,7 +6064,7 @@ func (monitors *IMonitors) Header() ([]string, error) {
 	return monitors.ctx.GetStringArray(data, cnt)
 }
 
-// Set Monitor mode (bitmask integer - see DSS Help)
+// Set Monitor mode (bitmask integer - see DSS Help and MonitorModes)
 func (monitors *IMonitors) Get_Mode() (int32, error) {
 	return (int32)(C.ctx_Monitors_Get_Mode(monitors.ctxPtr)), monitors.ctx.DSSError()
 }
@@ -3826,6 +6074,19 @@ func (monitors *IMonitors) Set_Mode(value int32) error {
 	return monitors.ctx.DSSError()
 }
 
+// Same as Get_Mode, typed with the MonitorModes enumeration. The value is a bitmask,
+// e.g. MonitorModes_Power | MonitorModes_Sequence.
+//
+// (API Extension)
+func (monitors *IMonitors) Get_ModeEnum() (MonitorModes, error) {
+	value, err := monitors.Get_Mode()
+	return (MonitorModes)(value), err
+}
+
+func (monitors *IMonitors) Set_ModeEnum(value MonitorModes) error {
+	return monitors.Set_Mode((int32)(value))
+}
+
 // Number of Channels in the active Monitor
 func (monitors *IMonitors) NumChannels() (int32, error) {
 	return (int32)(C.ctx_Monitors_Get_NumChannels(monitors.ctxPtr)), monitors.ctx.DSSError()
@@ -3863,6 +6124,76 @@ func (monitors *IMonitors) DblHour() ([]float64, error) {
 	return monitors.ctx.GetFloat64ArrayGR()
 }
 
+// Returns the voltage channels of the active VI-mode monitor, i.e. the channels named V1, V2, etc.
+// in the Header. Angle and residual channels are not included.
+//
+// (API Extension)
+func (monitors *IMonitors) VoltageChannels() ([][]float64, error) {
+	return monitors.phaseChannels('v')
+}
+
+// Returns the current channels of the active VI-mode monitor, i.e. the channels named I1, I2, etc.
+// in the Header. Angle and residual channels are not included.
+//
+// (API Extension)
+func (monitors *IMonitors) CurrentChannels() ([][]float64, error) {
+	return monitors.phaseChannels('i')
+}
+
+// Returns the data of the channels named as the prefix followed by the phase/conductor number,
+// decoded from a single ByteStream call through AllChannels.
+func (monitors *IMonitors) phaseChannels(prefix byte) ([][]float64, error) {
+	channels, header, err := monitors.AllChannels()
+	if err != nil {
+		return nil, err
+	}
+	var result [][]float64
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) < 2 || name[0] != prefix || name[1] < '0' || name[1] > '9' || strings.Contains(name, ".im") {
+			continue
+		}
+		result = append(result, channels[i])
+	}
+	return result, nil
+}
+
+// Creates a new VI-mode monitor with the residual channels enabled, monitoring the specified
+// terminal of the element (full name, e.g. "Line.650632"). The new monitor is named
+// "residual_<element>_<terminal>" (with dots replaced by underscores) and becomes the active monitor.
+//
+// Use ResidualChannel to extract the residual current after the solution.
+//
+// (API Extension)
+func (monitors *IMonitors) NewResidual(element string, terminal int32) error {
+	name := fmt.Sprintf("residual_%s_%d", strings.ReplaceAll(element, ".", "_"), terminal)
+	text := IText{}
+	text.Init(monitors.ctx)
+	err := text.Set_Command(fmt.Sprintf("new Monitor.%s element=%s terminal=%d mode=%d residual=yes", name, element, terminal, MonitorModes_VI))
+	if err != nil {
+		return err
+	}
+	return monitors.Set_Name(name)
+}
+
+// Returns the residual current channel (sum of the phase currents) of the active monitor.
+// The monitor must be a VI-mode monitor with the residual channels enabled, e.g. one created through NewResidual.
+//
+// (API Extension)
+func (monitors *IMonitors) ResidualChannel() ([]float64, error) {
+	header, err := monitors.Header()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.HasPrefix(name, "i") && strings.Contains(name, "resid") {
+			return monitors.Channel(int32(i + 1))
+		}
+	}
+	return nil, errors.New("(DSSError) The active monitor has no residual current channel.")
+}
+
 type IParser struct {
 	ICommonData
 }
@@ -3889,6 +6220,51 @@ func (parser *IParser) Vector(ExpectedSize int32) ([]float64, error) {
 	return parser.ctx.GetFloat64ArrayGR()
 }
 
+// Parses a vector of `size` values from `s`, e.g. "[1, 2, 3]", in a single call: sets the
+// command string, parses its first parameter as a vector and restores the previous command
+// string (parsing it again from the start).
+//
+// The present delimiters and quote characters are used as they are; they are not reset to
+// the defaults (see ResetDelimiters) nor changed.
+//
+// (API Extension)
+func (parser *IParser) ParseVector(s string, size int32) ([]float64, error) {
+	return parser.parseFirstParam(s, func() ([]float64, error) {
+		return parser.Vector(size)
+	})
+}
+
+// Same as ParseVector, for a square matrix of order `order`, e.g. "[1 2 | 3 4]". The result is
+// in the same format as Matrix.
+//
+// (API Extension)
+func (parser *IParser) ParseMatrix(s string, order int32) ([]float64, error) {
+	return parser.parseFirstParam(s, func() ([]float64, error) {
+		return parser.Matrix(order)
+	})
+}
+
+func (parser *IParser) parseFirstParam(s string, parse func() ([]float64, error)) ([]float64, error) {
+	prevCmdString, err := parser.Get_CmdString()
+	if err != nil {
+		return nil, err
+	}
+	if err = parser.Set_CmdString(s); err != nil {
+		return nil, err
+	}
+	var result []float64
+	if _, err = parser.NextParam(); err == nil {
+		result, err = parse()
+	}
+	if restoreErr := parser.Set_CmdString(prevCmdString); err == nil {
+		err = restoreErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (parser *IParser) ResetDelimiters() error {
 	C.ctx_Parser_ResetDelimiters(parser.ctxPtr)
 	return parser.ctx.DSSError()
@@ -3986,6 +6362,10 @@ func (parser *IParser) Set_WhiteSpace(value string) error {
 
 type IReduceCkt struct {
 	ICommonData
+
+	// Report of the last Do* call, see LastReductionReport
+	lastRemoved []string
+	lastErr     error
 }
 
 func (reduceckt *IReduceCkt) Init(ctx *DSSContextPtrs) {
@@ -4059,49 +6439,148 @@ func (reduceckt *IReduceCkt) SaveCircuit(CktName string) error {
 
 // Do Default Reduction algorithm
 func (reduceckt *IReduceCkt) DoDefault() error {
-	C.ctx_ReduceCkt_DoDefault(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoDefault(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 // Do ShortLines algorithm: Set Zmag first if you don't want the default
 func (reduceckt *IReduceCkt) DoShortLines() error {
-	C.ctx_ReduceCkt_DoShortLines(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoShortLines(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 // Reduce Dangling Algorithm; branches with nothing connected
 func (reduceckt *IReduceCkt) DoDangling() error {
-	C.ctx_ReduceCkt_DoDangling(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoDangling(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 func (reduceckt *IReduceCkt) DoLoopBreak() error {
-	C.ctx_ReduceCkt_DoLoopBreak(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoLoopBreak(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 func (reduceckt *IReduceCkt) DoParallelLines() error {
-	C.ctx_ReduceCkt_DoParallelLines(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoParallelLines(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 func (reduceckt *IReduceCkt) DoSwitches() error {
-	C.ctx_ReduceCkt_DoSwitches(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoSwitches(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 func (reduceckt *IReduceCkt) Do1phLaterals() error {
-	C.ctx_ReduceCkt_Do1phLaterals(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_Do1phLaterals(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
 }
 
 func (reduceckt *IReduceCkt) DoBranchRemove() error {
-	C.ctx_ReduceCkt_DoBranchRemove(reduceckt.ctxPtr)
-	return reduceckt.ctx.DSSError()
+	return reduceckt.track(func() error {
+		C.ctx_ReduceCkt_DoBranchRemove(reduceckt.ctxPtr)
+		return reduceckt.ctx.DSSError()
+	})
+}
+
+// Returns the full names of the PD elements removed (or disabled) by the last reduction
+// method called on this interface (DoDefault, DoShortLines, DoDangling, etc.), in circuit
+// order. The count of removed PD elements is simply len(removed).
+//
+// Only PD elements are tracked; a reduction may also move or merge other elements (e.g.
+// loads, when KeepLoad is enabled), which are not listed. The error is the one from the last
+// reduction, if any, including errors while building the report.
+//
+// Building the report iterates the PD elements before and after each reduction; the
+// reduction methods restore the previously active circuit element afterwards, if it
+// still exists.
+//
+// (API Extension)
+func (reduceckt *IReduceCkt) LastReductionReport() (removed []string, err error) {
+	return append([]string(nil), reduceckt.lastRemoved...), reduceckt.lastErr
+}
+
+// Runs a reduction, recording the PD elements enabled before it and not after. Listing the
+// PD elements changes the active PD and circuit element, so the previously active circuit
+// element is restored afterwards, unless the reduction removed it.
+func (reduceckt *IReduceCkt) track(reduce func() error) error {
+	reduceckt.lastRemoved = nil
+	restore := reduceckt.saveActiveCktElement()
+	defer func() {
+		// The element may have been removed by the reduction, nothing to restore then
+		restore()
+	}()
+	before, err := reduceckt.enabledPDElements()
+	if err == nil {
+		err = reduce()
+	}
+	var after []string
+	if err == nil {
+		after, err = reduceckt.enabledPDElements()
+	}
+	if err != nil {
+		reduceckt.lastErr = err
+		return err
+	}
+	present := make(map[string]bool, len(after))
+	for _, name := range after {
+		present[strings.ToLower(name)] = true
+	}
+	for _, name := range before {
+		if !present[strings.ToLower(name)] {
+			reduceckt.lastRemoved = append(reduceckt.lastRemoved, name)
+		}
+	}
+	reduceckt.lastErr = nil
+	return nil
+}
+
+// Lists the full names of the enabled PD elements.
+func (reduceckt *IReduceCkt) enabledPDElements() ([]string, error) {
+	pdelements := IPDElements{}
+	pdelements.Init(reduceckt.ctx)
+	cktelement := ICktElement{}
+	cktelement.Init(reduceckt.ctx)
+	names := make([]string, 0)
+	idx, err := pdelements.First()
+	for ; idx != 0 && err == nil; idx, err = pdelements.Next() {
+		var enabled bool
+		if enabled, err = cktelement.Get_Enabled(); err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+		var name string
+		if name, err = pdelements.Get_Name(); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
 }
 
 type ISolution struct {
 	ICommonData
+
+	// Callback set by SetProgressCallback
+	progressCallback func(pct float64, hour int32) bool
 }
 
 func (solution *ISolution) Init(ctx *DSSContextPtrs) {
@@ -4153,11 +6632,125 @@ func (solution *ISolution) Sample_DoControlActions() error {
 	return solution.ctx.DSSError()
 }
 
+// Performs a snapshot solution with the control devices disabled (control mode set to
+// ControlModes_Off), restoring the previous control mode afterwards. Useful to compute a
+// control-free reference case.
+//
+// (API Extension)
+func (solution *ISolution) SolveNoControlSnapshot() (err error) {
+	controlMode, err := solution.Get_ControlMode()
+	if err != nil {
+		return err
+	}
+	if err = solution.Set_ControlMode(ControlModes_Off); err != nil {
+		return err
+	}
+	defer func() {
+		restoreErr := solution.Set_ControlMode(controlMode)
+		if err == nil {
+			err = restoreErr
+		}
+	}()
+	return solution.SolveSnap()
+}
+
+// Runs a single control iteration: solves the circuit without controls, samples the
+// control devices, and executes the pending control actions. Returns `done` as true
+// when no more control actions are pending (see Get_ControlActionsDone).
+//
+// Useful for custom control loops, e.g.:
+//
+//	for done := false; !done; {
+//		if done, err = solution.RunControlIteration(); err != nil {
+//			break
+//		}
+//	}
+//
+// (API Extension)
+func (solution *ISolution) RunControlIteration() (done bool, err error) {
+	if err = solution.SolveNoControl(); err != nil {
+		return false, err
+	}
+	if err = solution.SampleControlDevices(); err != nil {
+		return false, err
+	}
+	if err = solution.DoControlActions(); err != nil {
+		return false, err
+	}
+	return solution.Get_ControlActionsDone()
+}
+
 func (solution *ISolution) Solve() error {
 	C.ctx_Solution_Solve(solution.ctxPtr)
 	return solution.ctx.DSSError()
 }
 
+// Sets a callback to report the progress of the time-series solutions (Daily, Yearly, DutyCycle,
+// Dynamic and Time modes) run by SolveContext, replacing the previous one; use nil to remove it.
+//
+// The callback is called after each time step with the percentage of the steps completed and
+// the solution hour. If it returns false, the solution stops after the step and SolveContext
+// returns an error. The other solution modes are not affected.
+//
+// Solve is not affected: it runs the whole solution in a single engine call, without calling
+// the callback. Use SolveContext (e.g. with context.Background()) to get the progress reports.
+//
+// (API Extension)
+func (solution *ISolution) SetProgressCallback(fn func(pct float64, hour int32) bool) {
+	solution.progressCallback = fn
+}
+
+// Same as Solve, but stops when `ctx` is cancelled, returning ctx.Err().
+//
+// The cancellation is checked before starting and, in the time-series modes (Daily, Yearly,
+// DutyCycle, Dynamic and Time), between time steps, which are solved one at a time. The other
+// modes cannot be interrupted once started. The progress callback, if set, is still called.
+//
+// (API Extension)
+func (solution *ISolution) SolveContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return solution.solveStepped(ctx)
+}
+
+// Returned by SolveContext when the progress callback aborts the solution
+var errSolutionAborted = errors.New("(DSSError) Solution aborted by the progress callback.")
+
+// Solves the time-series modes one step at a time through SolveSteps, calling the progress
+// callback and checking `ctx` after each step. Other modes are solved in a single call.
+func (solution *ISolution) solveStepped(ctx context.Context) error {
+	mode, err := solution.Get_Mode()
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case SolveModes_Daily, SolveModes_Yearly, SolveModes_DutyCycle, SolveModes_Dynamic, SolveModes_Time:
+	default:
+		C.ctx_Solution_Solve(solution.ctxPtr)
+		return solution.ctx.DSSError()
+	}
+	steps, err := solution.Get_Number()
+	if err != nil {
+		return err
+	}
+	fn := solution.progressCallback
+	step := int32(0)
+	return solution.SolveSteps(steps, func() error {
+		step++
+		if fn != nil {
+			hour, err := solution.Get_Hour()
+			if err != nil {
+				return err
+			}
+			if !fn(100*float64(step)/float64(steps), hour) {
+				return errSolutionAborted
+			}
+		}
+		return ctx.Err()
+	})
+}
+
 func (solution *ISolution) SolveDirect() error {
 	C.ctx_Solution_SolveDirect(solution.ctxPtr)
 	return solution.ctx.DSSError()
@@ -4193,6 +6786,18 @@ func (solution *ISolution) Set_AddType(value int32) error {
 	return solution.ctx.DSSError()
 }
 
+// Same as Get_AddType, typed with the AutoAddTypes enumeration.
+//
+// (API Extension)
+func (solution *ISolution) Get_AddTypeEnum() (AutoAddTypes, error) {
+	value, err := solution.Get_AddType()
+	return (AutoAddTypes)(value), err
+}
+
+func (solution *ISolution) Set_AddTypeEnum(value AutoAddTypes) error {
+	return solution.Set_AddType((int32)(value))
+}
+
 // Base Solution algorithm: {dssNormalSolve | dssNewtonSolve}
 func (solution *ISolution) Get_Algorithm() (SolutionAlgorithms, error) {
 	return (SolutionAlgorithms)(C.ctx_Solution_Get_Algorithm(solution.ctxPtr)), solution.ctx.DSSError()
@@ -4285,6 +6890,106 @@ func (solution *ISolution) EventLog() ([]string, error) {
 	return solution.ctx.GetStringArray(data, cnt)
 }
 
+// Control action recorded in the event log, see ISolution.ControlActions
+type ControlAction struct {
+	Hour        int32
+	Sec         float64
+	ControlIter int32
+	Element     string
+	Action      string
+}
+
+// Parses an event log entry in the format used by the engine for control actions, e.g.
+// "Hour=0, Sec=0, ControlIter=1, Element=Capacitor.c1, Action=**CLOSED**".
+func parseControlAction(entry string) (ControlAction, bool) {
+	var action ControlAction
+	elementPos := strings.Index(entry, "Element=")
+	actionPos := strings.Index(entry, ", Action=")
+	if elementPos < 0 || actionPos < elementPos {
+		return action, false
+	}
+	action.Element = strings.TrimSpace(entry[elementPos+len("Element=") : actionPos])
+	action.Action = strings.TrimSpace(entry[actionPos+len(", Action="):])
+	for _, field := range strings.Split(entry[:elementPos], ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.ToLower(kv[0]) {
+		case "hour":
+			if v, err := strconv.ParseInt(kv[1], 10, 32); err == nil {
+				action.Hour = int32(v)
+			}
+		case "sec":
+			if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+				action.Sec = v
+			}
+		case "controliter":
+			if v, err := strconv.ParseInt(kv[1], 10, 32); err == nil {
+				action.ControlIter = int32(v)
+			}
+		}
+	}
+	return action, true
+}
+
+// Control actions recorded in the event log for the present solution time (Hour and
+// Seconds), in the order they were taken. Entries that are not control actions are skipped.
+//
+// The event log is not cleared between solutions, so repeated solutions at the same time
+// (e.g. successive snapshot solutions) report the actions of all of them.
+//
+// (API Extension)
+func (solution *ISolution) ControlActions() ([]ControlAction, error) {
+	entries, err := solution.EventLog()
+	if err != nil {
+		return nil, err
+	}
+	hour, err := solution.Get_Hour()
+	if err != nil {
+		return nil, err
+	}
+	sec, err := solution.Get_Seconds()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ControlAction, 0)
+	for _, entry := range entries {
+		action, ok := parseControlAction(entry)
+		if !ok || action.Hour != hour {
+			continue
+		}
+		// The engine logs the seconds with 5 significant digits
+		if math.Abs(action.Sec-sec) > 1e-4*math.Max(1, math.Abs(sec)) {
+			continue
+		}
+		result = append(result, action)
+	}
+	return result, nil
+}
+
+// Full names of the control devices that acted at the present solution time, without
+// repetitions, in the order they first acted. See ControlActions for the details.
+//
+// (API Extension)
+func (solution *ISolution) ControlActionsTaken() ([]string, error) {
+	actions, err := solution.ControlActions()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(actions))
+	result := make([]string, 0, len(actions))
+	for _, action := range actions {
+		key := strings.ToLower(action.Element)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, action.Element)
+	}
+	return result, nil
+}
+
 // Set the Frequency for next solution
 func (solution *ISolution) Get_Frequency() (float64, error) {
 	return (float64)(C.ctx_Solution_Get_Frequency(solution.ctxPtr)), solution.ctx.DSSError()
@@ -4372,6 +7077,18 @@ func (solution *ISolution) Set_LoadModel(value int32) error {
 	return solution.ctx.DSSError()
 }
 
+// Same as Get_LoadModel, typed with the SolutionLoadModels enumeration.
+//
+// (API Extension)
+func (solution *ISolution) Get_LoadModelEnum() (SolutionLoadModels, error) {
+	value, err := solution.Get_LoadModel()
+	return (SolutionLoadModels)(value), err
+}
+
+func (solution *ISolution) Set_LoadModelEnum(value SolutionLoadModels) error {
+	return solution.Set_LoadModel((int32)(value))
+}
+
 // Default load multiplier applied to all non-fixed loads
 func (solution *ISolution) Get_LoadMult() (float64, error) {
 	return (float64)(C.ctx_Solution_Get_LoadMult(solution.ctxPtr)), solution.ctx.DSSError()
@@ -4552,6 +7269,24 @@ func (solution *ISolution) Set_StepsizeMin(value float64) error {
 	return solution.ctx.DSSError()
 }
 
+// Sets the step size to `minutes` and advances the solution time by one step, keeping
+// Hour, Seconds and dblHour consistent. The circuit is not solved; use e.g. SolveSnap or
+// SolveNoControl afterwards to solve at the new time. Note that Solve, in the time-series
+// modes, already advances the time by itself.
+//
+// (API Extension)
+func (solution *ISolution) StepMinutes(minutes float64) error {
+	err := solution.Set_StepsizeMin(minutes)
+	if err != nil {
+		return err
+	}
+	hour, err := solution.Get_dblHour()
+	if err != nil {
+		return err
+	}
+	return solution.Set_dblHour(hour + minutes/60)
+}
+
 func (solution *ISolution) BusLevels() ([]int32, error) {
 	C.ctx_Solution_Get_BusLevels_GR(solution.ctxPtr)
 	return solution.ctx.GetInt32ArrayGR()
@@ -4580,11 +7315,432 @@ func (solution *ISolution) Laplacian() ([]int32, error) {
 	C.ctx_Solution_Get_Laplacian_GR(solution.ctxPtr)
 	return solution.ctx.GetInt32ArrayGR()
 }
+
+// An edge of a Graph, connecting two buses through a PD element
+type GraphEdge struct {
+	Name string // Full name of the PD element
+	From int    // Index of the "from" bus in Graph.Buses
+	To   int    // Index of the "to" bus in Graph.Buses
+}
+
+// Graph representation of the circuit built from the incidence matrix, as returned by
+// ISolution.IncidenceGraph.
+type Graph struct {
+	Buses     []string    // Bus names, in the same order as IncMatrixCols
+	BusLevels []int32     // Level of each bus, i.e. the distance in branches from the source bus
+	Edges     []GraphEdge // One edge per branch, in the same order as IncMatrixRows
+	Adjacency [][]int     // For each bus, the indices of the adjacent buses
+}
+
+// Assembles the incidence matrix (IncMatrix, IncMatrixRows, IncMatrixCols) and the bus levels
+// (BusLevels) into a Graph structure.
+//
+// As with the individual getters, the incidence matrix must be computed first, e.g. with the
+// "CalcIncMatrix" or "CalcIncMatrix_O" commands.
+//
+// (API Extension)
+func (solution *ISolution) IncidenceGraph() (*Graph, error) {
+	triplets, err := solution.IncMatrix()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := solution.IncMatrixRows()
+	if err != nil {
+		return nil, err
+	}
+	cols, err := solution.IncMatrixCols()
+	if err != nil {
+		return nil, err
+	}
+	levels, err := solution.BusLevels()
+	if err != nil {
+		return nil, err
+	}
+	graph := &Graph{
+		Buses:     cols,
+		BusLevels: levels,
+		Edges:     make([]GraphEdge, len(rows)),
+		Adjacency: make([][]int, len(cols)),
+	}
+	for i := range graph.Edges {
+		graph.Edges[i] = GraphEdge{Name: rows[i], From: -1, To: -1}
+	}
+	// The incidence matrix is a list of (row, column, value) triplets,
+	// with value 1 for the "from" bus and -1 for the "to" bus.
+	for i := 0; i+2 < len(triplets); i += 3 {
+		row, col, value := int(triplets[i]), int(triplets[i+1]), triplets[i+2]
+		if row < 0 || row >= len(rows) || col < 0 || col >= len(cols) {
+			continue
+		}
+		if value > 0 {
+			graph.Edges[row].From = col
+		} else if value < 0 {
+			graph.Edges[row].To = col
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.From < 0 || edge.To < 0 {
+			continue
+		}
+		graph.Adjacency[edge.From] = append(graph.Adjacency[edge.From], edge.To)
+		graph.Adjacency[edge.To] = append(graph.Adjacency[edge.To], edge.From)
+	}
+	return graph, nil
+}
+
 func (solution *ISolution) SolveAll() error {
 	C.ctx_Solution_SolveAll(solution.ctxPtr)
 	return solution.ctx.DSSError()
 }
 
+// Solution state, as encoded by ISolution.SaveState
+type solutionState struct {
+	Mode              SolveModes
+	ControlMode       ControlModes
+	Number            int32
+	StepSize          float64
+	Hour              int32
+	Seconds           float64
+	LoadMult          float64
+	ControlIterations int32
+	NodeOrder         []string
+	NodeVoltages      []complex128
+}
+
+// Captures the state of the solution (solution mode, time, step size, load multiplier,
+// control counters and node voltages) as an opaque byte array, so that a run can be
+// resumed later with LoadState, e.g. after a crash.
+//
+// The circuit itself is not included; LoadState must be used with the same circuit.
+//
+// (API Extension)
+func (solution *ISolution) SaveState() ([]byte, error) {
+	var state solutionState
+	var err error
+	if state.Mode, err = solution.Get_Mode(); err != nil {
+		return nil, err
+	}
+	if state.ControlMode, err = solution.Get_ControlMode(); err != nil {
+		return nil, err
+	}
+	if state.Number, err = solution.Get_Number(); err != nil {
+		return nil, err
+	}
+	if state.StepSize, err = solution.Get_StepSize(); err != nil {
+		return nil, err
+	}
+	if state.Hour, err = solution.Get_Hour(); err != nil {
+		return nil, err
+	}
+	if state.Seconds, err = solution.Get_Seconds(); err != nil {
+		return nil, err
+	}
+	if state.LoadMult, err = solution.Get_LoadMult(); err != nil {
+		return nil, err
+	}
+	if state.ControlIterations, err = solution.Get_ControlIterations(); err != nil {
+		return nil, err
+	}
+	circuit := ICircuit{}
+	circuit.InitCommon(solution.ctx)
+	if state.NodeOrder, err = circuit.YNodeOrder(); err != nil {
+		return nil, err
+	}
+	if state.NodeVoltages, err = circuit.YNodeVarray(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(&state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Restores a solution state captured by SaveState. The node order of the circuit must
+// match the one of the saved state.
+//
+// (API Extension)
+func (solution *ISolution) LoadState(data []byte) error {
+	var state solutionState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state)
+	if err != nil {
+		return err
+	}
+	circuit := ICircuit{}
+	circuit.InitCommon(solution.ctx)
+	nodeOrder, err := circuit.YNodeOrder()
+	if err != nil {
+		return err
+	}
+	if len(nodeOrder) != len(state.NodeOrder) || len(state.NodeVoltages) != len(state.NodeOrder) {
+		return errors.New("(DSSError) The saved state does not match the active circuit.")
+	}
+	for i := range nodeOrder {
+		if !strings.EqualFold(nodeOrder[i], state.NodeOrder[i]) {
+			return errors.New("(DSSError) The saved state does not match the active circuit.")
+		}
+	}
+	// Setting the mode resets the time and step size, so it must come first
+	if err = solution.Set_Mode(state.Mode); err != nil {
+		return err
+	}
+	if err = solution.Set_ControlMode(state.ControlMode); err != nil {
+		return err
+	}
+	if err = solution.Set_Number(state.Number); err != nil {
+		return err
+	}
+	if err = solution.Set_StepSize(state.StepSize); err != nil {
+		return err
+	}
+	if err = solution.Set_Hour(state.Hour); err != nil {
+		return err
+	}
+	if err = solution.Set_Seconds(state.Seconds); err != nil {
+		return err
+	}
+	if err = solution.Set_LoadMult(state.LoadMult); err != nil {
+		return err
+	}
+	if err = solution.Set_ControlIterations(state.ControlIterations); err != nil {
+		return err
+	}
+	nodeV, err := solution.ctx.nodeVoltagesPtr()
+	if err != nil {
+		return err
+	}
+	if len(nodeV) != len(state.NodeVoltages)+1 {
+		return errors.New("(DSSError) The saved state does not match the active circuit.")
+	}
+	copy(nodeV[1:], state.NodeVoltages)
+	return nil
+}
+
+// Solves the next `steps` time steps of the current solution mode, one step at a time,
+// calling `onStep` (if not nil) after each step is solved.
+//
+// The solution Number is temporarily set to 1 so that each call to Solve advances a single
+// step; the previous value is restored before returning. Stops at the first error, either
+// from the engine or returned by `onStep`.
+//
+// See also TimeSeriesCollector.
+//
+// (API Extension)
+func (solution *ISolution) SolveSteps(steps int32, onStep func() error) (err error) {
+	number, err := solution.Get_Number()
+	if err != nil {
+		return err
+	}
+	if err = solution.Set_Number(1); err != nil {
+		return err
+	}
+	defer func() {
+		restoreErr := solution.Set_Number(number)
+		if err == nil {
+			err = restoreErr
+		}
+	}()
+	for i := int32(0); i < steps; i++ {
+		if err = solution.Solve(); err != nil {
+			return err
+		}
+		if onStep == nil {
+			continue
+		}
+		if err = onStep(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sparse solver options, i.e. which parts of the previous system Y matrix factorization
+// are reused in the next solution. The value combines one of the Reuse* options with the
+// AlwaysResetYPrimInvalid bit flag. Same as YMatrix.Get_SolverOptions, typed.
+//
+// ReuseCompressedMatrix is numerically exact. ReuseSymbolicFactorization and especially
+// ReuseNumericFactorization trade accuracy for speed: the numeric factorization is not
+// updated when the system Y matrix changes (e.g. a capacitor or tap change in a time-series
+// run), so the solution relies on the iterations to converge with the stale factors. This
+// may require more iterations, or fail to converge, after large changes. Use them only when
+// the topology and most of the Y matrix are fixed, and validate against ReuseNothing.
+//
+// (API Extension)
+func (solution *ISolution) Get_SolverOptions() (SparseSolverOptions, error) {
+	return (SparseSolverOptions)(C.ctx_YMatrix_Get_SolverOptions(solution.ctxPtr)), solution.ctx.DSSError()
+}
+
+func (solution *ISolution) Set_SolverOptions(value SparseSolverOptions) error {
+	C.ctx_YMatrix_Set_SolverOptions(solution.ctxPtr, (C.uint64_t)(value))
+	return solution.ctx.DSSError()
+}
+
+// Quantities that can be recorded by a TimeSeriesCollector
+type TimeSeriesQuantity int32
+
+const (
+	TimeSeriesQuantity_VoltageMag TimeSeriesQuantity = 0 // Node voltage magnitude, in volts
+	TimeSeriesQuantity_VoltagePU  TimeSeriesQuantity = 1 // Node voltage magnitude, in per unit
+	TimeSeriesQuantity_kW         TimeSeriesQuantity = 2 // Active power into the first terminal of an element, in kW
+	TimeSeriesQuantity_kvar       TimeSeriesQuantity = 3 // Reactive power into the first terminal of an element, in kvar
+	TimeSeriesQuantity_LosseskW   TimeSeriesQuantity = 4 // Active power losses of an element, in kW
+)
+
+// Result of a time-series collection. Hours contains the solution time (Solution.dblHour)
+// of each recorded step, and Series[i] contains the values of the quantity Names[i], one
+// value per step.
+type TimeSeriesResult struct {
+	Hours  []float64
+	Names  []string
+	Series [][]float64
+}
+
+type timeSeriesChannel struct {
+	target   string
+	quantity TimeSeriesQuantity
+}
+
+// TimeSeriesCollector records a list of node or element quantities at each solution step,
+// avoiding manual bookkeeping in time-series simulations. Typical usage:
+//
+//	collector := altdss.NewTimeSeriesCollector(&dss.ActiveCircuit)
+//	collector.AddNode("671.1", altdss.TimeSeriesQuantity_VoltagePU)
+//	err := dss.ActiveCircuit.Solution.SolveSteps(24, collector.Record)
+//	result := collector.Result()
+//
+// Note that recording changes the active bus and the active circuit element.
+//
+// (API Extension)
+type TimeSeriesCollector struct {
+	circuit  *ICircuit
+	channels []timeSeriesChannel
+	result   TimeSeriesResult
+}
+
+func NewTimeSeriesCollector(circuit *ICircuit) *TimeSeriesCollector {
+	return &TimeSeriesCollector{circuit: circuit}
+}
+
+// Adds a node voltage to be recorded. The node name uses the usual "bus.node" format, e.g. "671.1".
+func (collector *TimeSeriesCollector) AddNode(nodeName string, quantity TimeSeriesQuantity) error {
+	if quantity != TimeSeriesQuantity_VoltageMag && quantity != TimeSeriesQuantity_VoltagePU {
+		return fmt.Errorf("(DSSError) Invalid quantity for node \"%s\".", nodeName)
+	}
+	if !strings.Contains(nodeName, ".") {
+		return fmt.Errorf("(DSSError) Invalid node name \"%s\", expected \"bus.node\".", nodeName)
+	}
+	collector.addChannel(nodeName, quantity)
+	return nil
+}
+
+// Adds an element quantity to be recorded. The element is specified by its full name, e.g. "Line.650632".
+func (collector *TimeSeriesCollector) AddElement(fullName string, quantity TimeSeriesQuantity) error {
+	if quantity != TimeSeriesQuantity_kW && quantity != TimeSeriesQuantity_kvar && quantity != TimeSeriesQuantity_LosseskW {
+		return fmt.Errorf("(DSSError) Invalid quantity for element \"%s\".", fullName)
+	}
+	collector.addChannel(fullName, quantity)
+	return nil
+}
+
+func (collector *TimeSeriesCollector) addChannel(target string, quantity TimeSeriesQuantity) {
+	collector.channels = append(collector.channels, timeSeriesChannel{target, quantity})
+	collector.result.Names = append(collector.result.Names, target)
+	collector.result.Series = append(collector.result.Series, nil)
+}
+
+// Records the current value of all quantities, plus the current solution time.
+// This is meant to be passed to Solution.SolveSteps, but can be called manually after each solution.
+func (collector *TimeSeriesCollector) Record() error {
+	hour, err := collector.circuit.Solution.Get_dblHour()
+	if err != nil {
+		return err
+	}
+	values := make([]float64, len(collector.channels))
+	for i, channel := range collector.channels {
+		switch channel.quantity {
+		case TimeSeriesQuantity_VoltageMag, TimeSeriesQuantity_VoltagePU:
+			values[i], err = collector.readNode(channel)
+		default:
+			values[i], err = collector.readElement(channel)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	collector.result.Hours = append(collector.result.Hours, hour)
+	for i, value := range values {
+		collector.result.Series[i] = append(collector.result.Series[i], value)
+	}
+	return nil
+}
+
+func (collector *TimeSeriesCollector) readNode(channel timeSeriesChannel) (float64, error) {
+	sep := strings.Index(channel.target, ".")
+	busName := channel.target[:sep]
+	node, err := strconv.ParseInt(channel.target[sep+1:], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("(DSSError) Invalid node name \"%s\".", channel.target)
+	}
+	bus, err := collector.circuit.get_Buses(busName)
+	if err != nil {
+		return 0, err
+	}
+	if bus == nil {
+		return 0, fmt.Errorf("(DSSError) Bus \"%s\" not found.", busName)
+	}
+	nodes, err := bus.Nodes()
+	if err != nil {
+		return 0, err
+	}
+	var volts []complex128
+	if channel.quantity == TimeSeriesQuantity_VoltagePU {
+		volts, err = bus.PUVoltages()
+	} else {
+		volts, err = bus.Voltages()
+	}
+	if err != nil {
+		return 0, err
+	}
+	for i, n := range nodes {
+		if n == int32(node) && i < len(volts) {
+			return cmplx.Abs(volts[i]), nil
+		}
+	}
+	return 0, fmt.Errorf("(DSSError) Node \"%s\" not found.", channel.target)
+}
+
+func (collector *TimeSeriesCollector) readElement(channel timeSeriesChannel) (float64, error) {
+	idx, err := collector.circuit.SetActiveElement(channel.target)
+	if err != nil {
+		return 0, err
+	}
+	if idx < 0 {
+		return 0, fmt.Errorf("(DSSError) Element \"%s\" not found.", channel.target)
+	}
+	element := &collector.circuit.ActiveCktElement
+	if channel.quantity == TimeSeriesQuantity_LosseskW {
+		losses, err := element.Losses()
+		// Losses are reported in W
+		return real(losses) / 1000, err
+	}
+	powers, err := element.TotalPowers()
+	if err != nil {
+		return 0, err
+	}
+	if len(powers) == 0 {
+		return 0, fmt.Errorf("(DSSError) No power data for element \"%s\".", channel.target)
+	}
+	if channel.quantity == TimeSeriesQuantity_kvar {
+		return imag(powers[0]), nil
+	}
+	return real(powers[0]), nil
+}
+
+// Returns the data recorded so far.
+func (collector *TimeSeriesCollector) Result() *TimeSeriesResult {
+	return &collector.result
+}
+
 type ILineGeometries struct {
 	ICommonData
 }
@@ -5018,46 +8174,260 @@ func (loadshapes *ILoadShapes) Get_TimeArray() ([]float64, error) {
 	return loadshapes.ctx.GetFloat64ArrayGR()
 }
 
-func (loadshapes *ILoadShapes) Set_TimeArray(value []float64) error {
-	C.ctx_LoadShapes_Set_TimeArray(loadshapes.ctxPtr, (*C.double)(&value[0]), (C.int32_t)(len(value)))
-	return loadshapes.ctx.DSSError()
+func (loadshapes *ILoadShapes) Set_TimeArray(value []float64) error {
+	C.ctx_LoadShapes_Set_TimeArray(loadshapes.ctxPtr, (*C.double)(&value[0]), (C.int32_t)(len(value)))
+	return loadshapes.ctx.DSSError()
+}
+
+// Boolean flag to let Loads know to use the actual value in the curve rather than use the value as a multiplier.
+func (loadshapes *ILoadShapes) Get_UseActual() (bool, error) {
+	return (C.ctx_LoadShapes_Get_UseActual(loadshapes.ctxPtr) != 0), loadshapes.ctx.DSSError()
+}
+
+func (loadshapes *ILoadShapes) Set_UseActual(value bool) error {
+	C.ctx_LoadShapes_Set_UseActual(loadshapes.ctxPtr, ToUint16(value))
+	return loadshapes.ctx.DSSError()
+}
+
+func (loadshapes *ILoadShapes) Get_sInterval() (float64, error) {
+	return (float64)(C.ctx_LoadShapes_Get_SInterval(loadshapes.ctxPtr)), loadshapes.ctx.DSSError()
+}
+
+func (loadshapes *ILoadShapes) Set_sInterval(value float64) error {
+	C.ctx_LoadShapes_Set_SInterval(loadshapes.ctxPtr, (C.double)(value))
+	return loadshapes.ctx.DSSError()
+}
+
+// Converts the current LoadShape data to float32/single precision.
+// If there is no data or the data is already represented using float32, nothing is done.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) UseFloat32() error {
+	C.ctx_LoadShapes_UseFloat32(loadshapes.ctxPtr)
+	return loadshapes.ctx.DSSError()
+}
+
+// Converts the current LoadShape data to float64/double precision.
+// If there is no data or the data is already represented using float64, nothing is done.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) UseFloat64() error {
+	C.ctx_LoadShapes_UseFloat64(loadshapes.ctxPtr)
+	return loadshapes.ctx.DSSError()
+}
+
+// Sets the P multipliers of the active LoadShape from the column `column` (1-based) of a CSV
+// file, with `npts` points, enabling memory mapping (MemoryMapping=Yes) so that the data is
+// read from the file on demand instead of being copied to memory.
+//
+// The engine reads one column of the file, so arbitrary strides are not supported; use a
+// separate column per multiplier.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) SetPmultFromFile(path string, npts int32, column int32) error {
+	return loadshapes.setMultFromFile("mult", path, npts, column)
+}
+
+// Same as SetPmultFromFile, for the Q multipliers.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) SetQmultFromFile(path string, npts int32, column int32) error {
+	return loadshapes.setMultFromFile("qmult", path, npts, column)
+}
+
+func (loadshapes *ILoadShapes) setMultFromFile(propName string, path string, npts int32, column int32) error {
+	if npts <= 0 || column <= 0 {
+		return fmt.Errorf("(DSSError) Invalid number of points (%d) or column (%d).", npts, column)
+	}
+	name, err := loadshapes.Get_Name()
+	if err != nil {
+		return err
+	}
+	text := IText{}
+	text.Init(loadshapes.ctx)
+	return text.Set_Command(fmt.Sprintf("edit LoadShape.%s npts=%d MemoryMapping=Yes %s=(file=%s column=%d)", name, npts, propName, quotePropertyValue(path), column))
+}
+
+// Appends a point to the active LoadShape, growing its arrays by one element.
+//
+// The time value `timeHr` is only used for variable-interval shapes (interval=0); it is
+// ignored for fixed-interval shapes. The Q multiplier `q` is ignored if the shape has no
+// Q multipliers and `q` is zero; a non-zero `q` in this case results in an error since
+// the previous points have no Q data.
+//
+// Each call copies the existing data, so prefer Set_Pmult/Set_Qmult for large shapes.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) AppendPoint(p float64, q float64, timeHr float64) error {
+	npts, err := loadshapes.Get_Npts()
+	if err != nil {
+		return err
+	}
+	pmult, err := loadshapes.Get_Pmult()
+	if err != nil {
+		return err
+	}
+	qmult, err := loadshapes.Get_Qmult()
+	if err != nil {
+		return err
+	}
+	hrInterval, err := loadshapes.Get_HrInterval()
+	if err != nil {
+		return err
+	}
+	var timeArray []float64
+	if hrInterval == 0 {
+		if timeArray, err = loadshapes.Get_TimeArray(); err != nil {
+			return err
+		}
+	}
+	if int32(len(pmult)) < npts || (hrInterval == 0 && int32(len(timeArray)) < npts) {
+		return errors.New("(DSSError) Could not read the data of the active LoadShape.")
+	}
+	hasQ, err := loadshapes.hasQmult()
+	if err != nil {
+		return err
+	}
+	if hasQ && int32(len(qmult)) < npts {
+		return errors.New("(DSSError) Could not read the data of the active LoadShape.")
+	}
+	if !hasQ && npts > 0 && q != 0 {
+		return errors.New("(DSSError) The active LoadShape has no Q multipliers.")
+	}
+	pmult = append(pmult[:npts], p)
+	if err = loadshapes.Set_Npts(npts + 1); err != nil {
+		return err
+	}
+	if err = loadshapes.Set_Pmult(pmult); err != nil {
+		return err
+	}
+	if hasQ || (npts == 0 && q != 0) {
+		if err = loadshapes.Set_Qmult(append(qmult[:npts], q)); err != nil {
+			return err
+		}
+	}
+	if hrInterval == 0 {
+		return loadshapes.Set_TimeArray(append(timeArray[:npts], timeHr))
+	}
+	return nil
+}
+
+// Reports whether the active LoadShape has Q multipliers, from its Qmult property. The
+// length of Get_Qmult cannot tell: without Q data, the engine returns a default array of
+// one point, like the Q data of a one-point shape.
+func (loadshapes *ILoadShapes) hasQmult() (bool, error) {
+	name, err := loadshapes.Get_Name()
+	if err != nil {
+		return false, err
+	}
+	// Also makes the LoadShape the active DSS object, used by the Properties interface
+	if err = loadshapes.Set_Name(name); err != nil {
+		return false, err
+	}
+	properties := IDSSProperty{}
+	properties.Init(loadshapes.ctx)
+	if err = properties.Set_Name("Qmult"); err != nil {
+		return false, err
+	}
+	value, err := properties.Get_Val()
+	if err != nil {
+		return false, err
+	}
+	return strings.Trim(value, "[](){}\"' \t") != "", nil
 }
 
-// Boolean flag to let Loads know to use the actual value in the curve rather than use the value as a multiplier.
-func (loadshapes *ILoadShapes) Get_UseActual() (bool, error) {
-	return (C.ctx_LoadShapes_Get_UseActual(loadshapes.ctxPtr) != 0), loadshapes.ctx.DSSError()
-}
+// Returns the maximum and the mean of the multipliers of the active LoadShape. For a
+// variable-interval shape (HrInterval is zero), the mean is weighted by time, interpolating
+// linearly between the points of the time array as the engine does; otherwise all points
+// have the same weight.
+func (loadshapes *ILoadShapes) multStats(q bool) (peak float64, mean float64, err error) {
+	npts, err := loadshapes.Get_Npts()
+	if err != nil {
+		return 0, 0, err
+	}
+	var mult []float64
+	if q {
+		var hasQ bool
+		if hasQ, err = loadshapes.hasQmult(); err != nil {
+			return 0, 0, err
+		}
+		if !hasQ {
+			return 0, 0, errors.New("(DSSError) The active LoadShape has no Q multipliers.")
+		}
+		mult, err = loadshapes.Get_Qmult()
+	} else {
+		mult, err = loadshapes.Get_Pmult()
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if npts == 0 || len(mult) == 0 {
+		return 0, 0, errors.New("(DSSError) The active LoadShape has no multipliers.")
+	}
+	peak = mult[0]
+	for _, v := range mult {
+		peak = math.Max(peak, v)
+		mean += v
+	}
+	mean /= float64(len(mult))
 
-func (loadshapes *ILoadShapes) Set_UseActual(value bool) error {
-	C.ctx_LoadShapes_Set_UseActual(loadshapes.ctxPtr, ToUint16(value))
-	return loadshapes.ctx.DSSError()
+	hrInterval, err := loadshapes.Get_HrInterval()
+	if err != nil || hrInterval != 0 {
+		return peak, mean, err
+	}
+	timeArray, err := loadshapes.Get_TimeArray()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(timeArray) < len(mult) || len(mult) < 2 {
+		return peak, mean, nil
+	}
+	span := timeArray[len(mult)-1] - timeArray[0]
+	if span <= 0 {
+		return peak, mean, nil
+	}
+	area := 0.0
+	for i := 1; i < len(mult); i++ {
+		area += (mult[i] + mult[i-1]) / 2 * (timeArray[i] - timeArray[i-1])
+	}
+	return peak, area / span, nil
 }
 
-func (loadshapes *ILoadShapes) Get_sInterval() (float64, error) {
-	return (float64)(C.ctx_LoadShapes_Get_SInterval(loadshapes.ctxPtr)), loadshapes.ctx.DSSError()
+// Maximum of the P multipliers of the active LoadShape, computed from Get_Pmult. Read it
+// before Normalize to get the peak of the original data.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) Get_MaxP() (float64, error) {
+	peak, _, err := loadshapes.multStats(false)
+	return peak, err
 }
 
-func (loadshapes *ILoadShapes) Set_sInterval(value float64) error {
-	C.ctx_LoadShapes_Set_SInterval(loadshapes.ctxPtr, (C.double)(value))
-	return loadshapes.ctx.DSSError()
+// Maximum of the Q multipliers of the active LoadShape, computed from Get_Qmult. Returns an
+// error if the shape has no Q multipliers.
+//
+// (API Extension)
+func (loadshapes *ILoadShapes) Get_MaxQ() (float64, error) {
+	peak, _, err := loadshapes.multStats(true)
+	return peak, err
 }
 
-// Converts the current LoadShape data to float32/single precision.
-// If there is no data or the data is already represented using float32, nothing is done.
+// Mean of the P multipliers of the active LoadShape, computed from Get_Pmult. For shapes
+// with a time array (HrInterval is zero), the mean is weighted by time, interpolating
+// linearly between points.
 //
 // (API Extension)
-func (loadshapes *ILoadShapes) UseFloat32() error {
-	C.ctx_LoadShapes_UseFloat32(loadshapes.ctxPtr)
-	return loadshapes.ctx.DSSError()
+func (loadshapes *ILoadShapes) Get_MeanP() (float64, error) {
+	_, mean, err := loadshapes.multStats(false)
+	return mean, err
 }
 
-// Converts the current LoadShape data to float64/double precision.
-// If there is no data or the data is already represented using float64, nothing is done.
+// Mean of the Q multipliers of the active LoadShape, computed from Get_Qmult, weighted by
+// time as in Get_MeanP. Returns an error if the shape has no Q multipliers.
 //
 // (API Extension)
-func (loadshapes *ILoadShapes) UseFloat64() error {
-	C.ctx_LoadShapes_UseFloat64(loadshapes.ctxPtr)
-	return loadshapes.ctx.DSSError()
+func (loadshapes *ILoadShapes) Get_MeanQ() (float64, error) {
+	_, mean, err := loadshapes.multStats(true)
+	return mean, err
 }
 
 type ILoads struct {
@@ -5116,6 +8486,58 @@ func (loads *ILoads) Set_idx(value int32) error {
 	return loads.ctx.DSSError()
 }
 
+// Iterator over the loads, positioned on each load in turn, for use with
+// range-over-func (Go 1.23+; the type is equivalent to `iter.Seq[*ILoads]`):
+//
+//	for elem := range circuit.Loads.All() { ... }
+//
+// Disabled elements are included according to the IterateDisabled setting. The iteration
+// stops at the first error; use AllWithError to get it. The previously active load is
+// restored at the end of the loop, including when the loop exits early.
+//
+// (API Extension)
+func (loads *ILoads) All() func(yield func(*ILoads) bool) {
+	return func(yield func(*ILoads) bool) {
+		loads.iterate(func() bool {
+			return yield(loads)
+		})
+	}
+}
+
+// Same as All, also yielding the errors (the type is equivalent to
+// `iter.Seq2[*ILoads, error]`):
+//
+//	for elem, err := range circuit.Loads.AllWithError() { ... }
+//
+// If iterating or restoring the previously active load fails, the last pair yielded is
+// (nil, err).
+//
+// (API Extension)
+func (loads *ILoads) AllWithError() func(yield func(*ILoads, error) bool) {
+	return func(yield func(*ILoads, error) bool) {
+		iterateWithError(loads.iterate, func(err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(loads, nil)
+		})
+	}
+}
+
+// Runs the First/Next loop for All and AllWithError, restoring the previously active load.
+func (loads *ILoads) iterate(yield func() bool) error {
+	prevIdx, err := loads.Get_idx()
+	if err != nil {
+		return err
+	}
+	return iterateCollection(loads.First, loads.Next, func() error {
+		if prevIdx > 0 {
+			return loads.Set_idx(prevIdx)
+		}
+		return nil
+	}, yield)
+}
+
 // Factor for allocating loads by connected xfkva
 func (loads *ILoads) Get_AllocationFactor() (float64, error) {
 	return (float64)(C.ctx_Loads_Get_AllocationFactor(loads.ctxPtr)), loads.ctx.DSSError()
@@ -5485,6 +8907,116 @@ func (loads *ILoads) Set_Phases(value int32) error {
 	return loads.ctx.DSSError()
 }
 
+// Assigns the daily loadshapes of several loads in a single pass over the loads.
+// The `mapping` is keyed by load name (without the "Load." prefix; case-insensitive),
+// with the loadshape names as values.
+//
+// Returns an error listing the loads not found, after assigning the others.
+//
+// (API Extension)
+func (loads *ILoads) AssignDailyShapes(mapping map[string]string) error {
+	pending := make(map[string]string, len(mapping))
+	for name, shape := range mapping {
+		pending[strings.ToLower(name)] = shape
+	}
+	idx, err := loads.First()
+	for ; idx != 0 && err == nil && len(pending) != 0; idx, err = loads.Next() {
+		name, err := loads.Get_Name()
+		if err != nil {
+			return err
+		}
+		name = strings.ToLower(name)
+		shape, found := pending[name]
+		if !found {
+			continue
+		}
+		if err = loads.Set_daily(shape); err != nil {
+			return err
+		}
+		delete(pending, name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(pending) != 0 {
+		missing := make([]string, 0, len(pending))
+		for name := range pending {
+			missing = append(missing, name)
+		}
+		return notFoundError("Loads", missing)
+	}
+	return nil
+}
+
+// Complex power, in kVA, for each conductor of the active load after the solution,
+// as flowing into the terminal, i.e. the power actually consumed.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (loads *ILoads) Powers() ([]complex128, error) {
+	name, err := loads.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return loads.elementPowers("Load." + name)
+}
+
+// Total complex power, in kVA, consumed by the active load after the solution. This differs
+// from the nominal values (Get_kW, Get_kvar) when the voltage or the load shapes change the demand.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (loads *ILoads) TotalPower() (complex128, error) {
+	name, err := loads.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return loads.elementTotalPower("Load." + name)
+}
+
+// Returns the voltage limits of the load model, [Vminpu, Vmaxpu], for each load, keyed by
+// load name. Outside these limits, the loads revert to constant impedance.
+//
+// The active load is preserved, also on errors.
+//
+// (API Extension)
+func (loads *ILoads) VoltageBoundsReport() (result map[string][2]float64, err error) {
+	prevIdx, err := loads.Get_idx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if prevIdx <= 0 {
+			return
+		}
+		if restoreErr := loads.Set_idx(prevIdx); err == nil && restoreErr != nil {
+			result, err = nil, restoreErr
+		}
+	}()
+	result = make(map[string][2]float64)
+	idx, err := loads.First()
+	for ; idx != 0 && err == nil; idx, err = loads.Next() {
+		var bounds [2]float64
+		name, err := loads.Get_Name()
+		if err != nil {
+			return nil, err
+		}
+		if bounds[0], err = loads.Get_Vminpu(); err != nil {
+			return nil, err
+		}
+		if bounds[1], err = loads.Get_Vmaxpu(); err != nil {
+			return nil, err
+		}
+		result[name] = bounds
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 type IMeters struct {
 	ICommonData
 }
@@ -5743,6 +9275,36 @@ func (meters *IMeters) RegisterValues() ([]float64, error) {
 	return meters.ctx.GetFloat64ArrayGR()
 }
 
+// Register values of the active energy meter, keyed by the register names (see RegisterNames).
+//
+// (API Extension)
+func (meters *IMeters) RegisterValuesMap() (map[string]float64, error) {
+	names, err := meters.RegisterNames()
+	if err != nil {
+		return nil, err
+	}
+	values, err := meters.RegisterValues()
+	if err != nil {
+		return nil, err
+	}
+	return registerValuesMap(names, values)
+}
+
+// Value of a single register of the active energy meter, by name (case-insensitive), e.g. "kWh".
+//
+// (API Extension)
+func (meters *IMeters) Register(name string) (float64, error) {
+	names, err := meters.RegisterNames()
+	if err != nil {
+		return 0, err
+	}
+	values, err := meters.RegisterValues()
+	if err != nil {
+		return 0, err
+	}
+	return registerValue(names, values, name)
+}
+
 // SAIDI for this meter's zone. Execute DoReliabilityCalc first.
 func (meters *IMeters) SAIDI() (float64, error) {
 	return (float64)(C.ctx_Meters_Get_SAIDI(meters.ctxPtr)), meters.ctx.DSSError()
@@ -5799,6 +9361,53 @@ func (meters *IMeters) Totals() ([]float64, error) {
 	return meters.ctx.GetFloat64ArrayGR()
 }
 
+// Feeder section data of the active EnergyMeter, as returned by IMeters.SectionReport
+type SectionInfo struct {
+	SectionIndex        int32   // Index of the section in the meter zone
+	SeqIdx              int32   // SequenceIndex of the branch at the head of this section
+	NumBranches         int32   // Number of branches (lines) in this section
+	NumCustomers        int32   // Number of customers in this section
+	TotalCustomers      int32   // Total customers downline from this section
+	OCPDeviceType       int32   // Type of OCP device. 1=Fuse; 2=Recloser; 3=Relay
+	SumBranchFltRates   float64 // Sum of the branch fault rates in this section
+	AvgRepairTime       float64 // Average repair time in this section
+	FaultRateXRepairHrs float64 // Sum of fault rate times repair hours in this section
+}
+
+// Activates the section `SectIdx` of the active EnergyMeter and returns its data in a single structure.
+//
+// (API Extension)
+func (meters *IMeters) SectionReport(SectIdx int32) (SectionInfo, error) {
+	info := SectionInfo{SectionIndex: SectIdx}
+	err := meters.SetActiveSection(SectIdx)
+	if err != nil {
+		return info, err
+	}
+	if info.SeqIdx, err = meters.SectSeqIdx(); err != nil {
+		return info, err
+	}
+	if info.NumBranches, err = meters.NumSectionBranches(); err != nil {
+		return info, err
+	}
+	if info.NumCustomers, err = meters.NumSectionCustomers(); err != nil {
+		return info, err
+	}
+	if info.TotalCustomers, err = meters.SectTotalCust(); err != nil {
+		return info, err
+	}
+	if info.OCPDeviceType, err = meters.OCPDeviceType(); err != nil {
+		return info, err
+	}
+	if info.SumBranchFltRates, err = meters.SumBranchFltRates(); err != nil {
+		return info, err
+	}
+	if info.AvgRepairTime, err = meters.AvgRepairTime(); err != nil {
+		return info, err
+	}
+	info.FaultRateXRepairHrs, err = meters.FaultRateXRepairHrs()
+	return info, err
+}
+
 type IPDElements struct {
 	ICommonData
 }
@@ -5871,6 +9480,58 @@ func (pdelements *IPDElements) Next() (int32, error) {
 	return (int32)(C.ctx_PDElements_Get_Next(pdelements.ctxPtr)), pdelements.ctx.DSSError()
 }
 
+// Iterator over the PD elements, positioned on each PD element in turn, for use with
+// range-over-func (Go 1.23+; the type is equivalent to `iter.Seq[*IPDElements]`):
+//
+//	for elem := range circuit.PDElements.All() { ... }
+//
+// Disabled elements are included according to the IterateDisabled setting. The iteration
+// stops at the first error; use AllWithError to get it. The previously active PD element is
+// restored at the end of the loop, including when the loop exits early.
+//
+// (API Extension)
+func (pdelements *IPDElements) All() func(yield func(*IPDElements) bool) {
+	return func(yield func(*IPDElements) bool) {
+		pdelements.iterate(func() bool {
+			return yield(pdelements)
+		})
+	}
+}
+
+// Same as All, also yielding the errors (the type is equivalent to
+// `iter.Seq2[*IPDElements, error]`):
+//
+//	for elem, err := range circuit.PDElements.AllWithError() { ... }
+//
+// If iterating or restoring the previously active PD element fails, the last pair yielded is
+// (nil, err).
+//
+// (API Extension)
+func (pdelements *IPDElements) AllWithError() func(yield func(*IPDElements, error) bool) {
+	return func(yield func(*IPDElements, error) bool) {
+		iterateWithError(pdelements.iterate, func(err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(pdelements, nil)
+		})
+	}
+}
+
+// Runs the First/Next loop for All and AllWithError, restoring the previously active PD element.
+func (pdelements *IPDElements) iterate(yield func() bool) error {
+	prevName, err := pdelements.Get_Name()
+	if err != nil {
+		return err
+	}
+	return iterateCollection(pdelements.First, pdelements.Next, func() error {
+		if prevName != "" {
+			return pdelements.Set_Name(prevName)
+		}
+		return nil
+	}, yield)
+}
+
 // Number of customers, this branch
 func (pdelements *IPDElements) Numcustomers() (int32, error) {
 	return (int32)(C.ctx_PDElements_Get_Numcustomers(pdelements.ctxPtr)), pdelements.ctx.DSSError()
@@ -6047,6 +9708,69 @@ func (pdelements *IPDElements) AllNumTerminals() ([]int32, error) {
 	return pdelements.ctx.GetInt32ArrayGR()
 }
 
+// Returns the fault rates of all enabled PD elements, keyed by full element name.
+// See Get_FaultRate.
+//
+// (API Extension)
+func (pdelements *IPDElements) AllFaultRates() (map[string]float64, error) {
+	result := make(map[string]float64)
+	idx, err := pdelements.First()
+	for ; idx != 0 && err == nil; idx, err = pdelements.Next() {
+		name, err := pdelements.Get_Name()
+		if err != nil {
+			return nil, err
+		}
+		if result[name], err = pdelements.Get_FaultRate(); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Sets the fault rates of the PD elements listed in `rates`, keyed by full element name
+// (e.g. "Line.650632"; case-insensitive), in a single pass over the enabled PD elements.
+// See Set_FaultRate.
+//
+// Returns an error listing the names not found, after applying the others.
+//
+// (API Extension)
+func (pdelements *IPDElements) SetAllFaultRates(rates map[string]float64) error {
+	pending := make(map[string]float64, len(rates))
+	for name, rate := range rates {
+		pending[strings.ToLower(name)] = rate
+	}
+	idx, err := pdelements.First()
+	for ; idx != 0 && err == nil && len(pending) != 0; idx, err = pdelements.Next() {
+		name, err := pdelements.Get_Name()
+		if err != nil {
+			return err
+		}
+		name = strings.ToLower(name)
+		rate, found := pending[name]
+		if !found {
+			continue
+		}
+		if err = pdelements.Set_FaultRate(rate); err != nil {
+			return err
+		}
+		delete(pending, name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(pending) != 0 {
+		missing := make([]string, 0, len(pending))
+		for name := range pending {
+			missing = append(missing, name)
+		}
+		return notFoundError("PD elements", missing)
+	}
+	return nil
+}
+
 type IPVSystems struct {
 	ICommonData
 }
@@ -6137,6 +9861,36 @@ func (pvsystems *IPVSystems) RegisterValues() ([]float64, error) {
 	return pvsystems.ctx.GetFloat64ArrayGR()
 }
 
+// Register values of the active PV system, keyed by the register names (see RegisterNames).
+//
+// (API Extension)
+func (pvsystems *IPVSystems) RegisterValuesMap() (map[string]float64, error) {
+	names, err := pvsystems.RegisterNames()
+	if err != nil {
+		return nil, err
+	}
+	values, err := pvsystems.RegisterValues()
+	if err != nil {
+		return nil, err
+	}
+	return registerValuesMap(names, values)
+}
+
+// Value of a single register of the active PV system, by name (case-insensitive), e.g. "kWh".
+//
+// (API Extension)
+func (pvsystems *IPVSystems) Register(name string) (float64, error) {
+	names, err := pvsystems.RegisterNames()
+	if err != nil {
+		return 0, err
+	}
+	values, err := pvsystems.RegisterValues()
+	if err != nil {
+		return 0, err
+	}
+	return registerValue(names, values, name)
+}
+
 // Get/set Rated kVA of the PVSystem
 func (pvsystems *IPVSystems) Get_kVArated() (float64, error) {
 	return (float64)(C.ctx_PVSystems_Get_kVArated(pvsystems.ctxPtr)), pvsystems.ctx.DSSError()
@@ -6288,6 +10042,76 @@ func (pvsystems *IPVSystems) Sensor() (string, error) {
 	return C.GoString(C.ctx_PVSystems_Get_Sensor(pvsystems.ctxPtr)), pvsystems.ctx.DSSError()
 }
 
+// Reads a numeric property of the active PV system.
+func (pvsystems *IPVSystems) getProperty(propName string) (float64, error) {
+	name, err := pvsystems.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return pvsystems.getElementPropertyFloat("PVSystem."+name, propName)
+}
+
+// Sets a numeric property of the active PV system.
+func (pvsystems *IPVSystems) setProperty(propName string, value float64) error {
+	name, err := pvsystems.Get_Name()
+	if err != nil {
+		return err
+	}
+	return pvsystems.setElementProperty("PVSystem."+name, propName, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Maximum reactive power generation (kvar) of the inverter of the active PV system.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (pvsystems *IPVSystems) Get_kvarLimit() (float64, error) {
+	return pvsystems.getProperty("kvarMax")
+}
+
+func (pvsystems *IPVSystems) Set_kvarLimit(value float64) error {
+	return pvsystems.setProperty("kvarMax", value)
+}
+
+// Maximum reactive power absorption (kvar, as a positive value) of the inverter of the active PV system.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (pvsystems *IPVSystems) Get_kvarLimitNeg() (float64, error) {
+	return pvsystems.getProperty("kvarMaxAbs")
+}
+
+func (pvsystems *IPVSystems) Set_kvarLimitNeg(value float64) error {
+	return pvsystems.setProperty("kvarMaxAbs", value)
+}
+
+// Minimum per-unit voltage for which the model of the active PV system is assumed to apply.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (pvsystems *IPVSystems) Get_Vminpu() (float64, error) {
+	return pvsystems.getProperty("Vminpu")
+}
+
+func (pvsystems *IPVSystems) Set_Vminpu(value float64) error {
+	return pvsystems.setProperty("Vminpu", value)
+}
+
+// Maximum per-unit voltage for which the model of the active PV system is assumed to apply.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (pvsystems *IPVSystems) Get_Vmaxpu() (float64, error) {
+	return pvsystems.getProperty("Vmaxpu")
+}
+
+func (pvsystems *IPVSystems) Set_Vmaxpu(value float64) error {
+	return pvsystems.setProperty("Vmaxpu", value)
+}
+
 type IReactors struct {
 	ICommonData
 }
@@ -6571,6 +10395,78 @@ func (reactors *IReactors) Set_Z0(value complex128) error {
 	return reactors.ctx.DSSError()
 }
 
+// Complex power, in kVA, for each conductor of each terminal of the active reactor after the
+// solution, as flowing into the terminals. The active circuit element is preserved.
+//
+// (API Extension)
+func (reactors *IReactors) Powers() ([]complex128, error) {
+	name, err := reactors.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return reactors.elementPowers("Reactor." + name)
+}
+
+// Complex currents, in amperes, for each conductor of each terminal of the active reactor after
+// the solution. The active circuit element is preserved.
+//
+// (API Extension)
+func (reactors *IReactors) Currents() ([]complex128, error) {
+	name, err := reactors.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return reactors.elementCurrents("Reactor." + name)
+}
+
+// Reactive power absorbed by each phase of the active reactor after the solution, in kvar,
+// from the power flowing into the phase conductors of the first terminal. Under unbalanced
+// voltages, the values differ from the nameplate rating. The active circuit element is
+// preserved.
+//
+// (API Extension)
+func (reactors *IReactors) KvarAbsorbedByPhase() (result []float64, err error) {
+	name, err := reactors.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	err = reactors.withCktElement("Reactor."+name, func(cktelement *ICktElement) error {
+		powers, err := cktelement.Powers()
+		if err != nil {
+			return err
+		}
+		numPhases, err := cktelement.NumPhases()
+		if err != nil {
+			return err
+		}
+		if int(numPhases) > len(powers) {
+			return errors.New("(DSSError) The reactor powers are not available.")
+		}
+		result = make([]float64, numPhases)
+		for i := range result {
+			result[i] = imag(powers[i])
+		}
+		return nil
+	})
+	return
+}
+
+// Total reactive power absorbed by the active reactor after the solution, in kvar; the sum
+// of KvarAbsorbedByPhase. The active circuit element is preserved.
+//
+// (API Extension)
+func (reactors *IReactors) KvarAbsorbed() (float64, error) {
+	kvar, err := reactors.KvarAbsorbedByPhase()
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, q := range kvar {
+		total += q
+	}
+	return total, nil
+}
+
 type IReclosers struct {
 	ICommonData
 }
@@ -7173,8 +11069,8 @@ func (relays *IRelays) Reset() error {
 }
 
 // Get/Set present state of relay.
-// If set to open, open relay's controlled element and lock out the relay.
-// If set to close, close relay's controlled element and resets relay to first operation.
+// If set to open (ActionCodes_Open=1), open relay's controlled element and lock out the relay.
+// If set to close (ActionCodes_Close=2), close relay's controlled element and resets relay to first operation.
 func (relays *IRelays) Get_State() (int32, error) {
 	return (int32)(C.ctx_Relays_Get_State(relays.ctxPtr)), relays.ctx.DSSError()
 }
@@ -7184,7 +11080,7 @@ func (relays *IRelays) Set_State(value int32) error {
 	return relays.ctx.DSSError()
 }
 
-// Normal state of relay.
+// Normal state (ActionCodes_Open=1, ActionCodes_Close=2) of relay.
 func (relays *IRelays) Get_NormalState() (int32, error) {
 	return (int32)(C.ctx_Relays_Get_NormalState(relays.ctxPtr)), relays.ctx.DSSError()
 }
@@ -7453,6 +11349,19 @@ func (swtcontrols *ISwtControls) Set_Action(value int32) error {
 	return swtcontrols.ctx.DSSError()
 }
 
+// Same as Get_Action, typed with the ActionCodes enumeration (ActionCodes_Open or
+// ActionCodes_Close).
+//
+// (API Extension)
+func (swtcontrols *ISwtControls) Get_ActionEnum() (ActionCodes, error) {
+	value, err := swtcontrols.Get_Action()
+	return (ActionCodes)(value), err
+}
+
+func (swtcontrols *ISwtControls) Set_ActionEnum(value ActionCodes) error {
+	return swtcontrols.Set_Action((int32)(value))
+}
+
 // Time delay [s] betwen arming and opening or closing the switch.  Control may reset before actually operating the switch.
 func (swtcontrols *ISwtControls) Get_Delay() (float64, error) {
 	return (float64)(C.ctx_SwtControls_Get_Delay(swtcontrols.ctxPtr)), swtcontrols.ctx.DSSError()
@@ -7735,7 +11644,8 @@ func (text *IText) Init(ctx *DSSContextPtrs) {
 }
 
 // Runs a list of strings as commands directly in the DSS engine.
-// Intermediate results are ignored.
+// Intermediate results are ignored. When EarlyAbort is enabled (see IError), the
+// commands after the first error are not executed.
 //
 // (API Extension)
 func (text *IText) Commands(value []string) error {
@@ -7773,6 +11683,17 @@ func (text *IText) Result() (string, error) {
 	return C.GoString(C.ctx_Text_Get_Result(text.ctxPtr)), text.ctx.DSSError()
 }
 
+// Runs a single command in the DSS engine and returns its result string,
+// i.e. Set_Command followed by Result.
+//
+// (API Extension)
+func (text *IText) Execute(command string) (string, error) {
+	if err := text.Set_Command(command); err != nil {
+		return "", err
+	}
+	return text.Result()
+}
+
 type ITopology struct {
 	ICommonData
 }
@@ -7950,6 +11871,58 @@ func (transformers *ITransformers) Set_idx(value int32) error {
 	return transformers.ctx.DSSError()
 }
 
+// Iterator over the transformers, positioned on each transformer in turn, for use with
+// range-over-func (Go 1.23+; the type is equivalent to `iter.Seq[*ITransformers]`):
+//
+//	for elem := range circuit.Transformers.All() { ... }
+//
+// Disabled elements are included according to the IterateDisabled setting. The iteration
+// stops at the first error; use AllWithError to get it. The previously active transformer is
+// restored at the end of the loop, including when the loop exits early.
+//
+// (API Extension)
+func (transformers *ITransformers) All() func(yield func(*ITransformers) bool) {
+	return func(yield func(*ITransformers) bool) {
+		transformers.iterate(func() bool {
+			return yield(transformers)
+		})
+	}
+}
+
+// Same as All, also yielding the errors (the type is equivalent to
+// `iter.Seq2[*ITransformers, error]`):
+//
+//	for elem, err := range circuit.Transformers.AllWithError() { ... }
+//
+// If iterating or restoring the previously active transformer fails, the last pair yielded is
+// (nil, err).
+//
+// (API Extension)
+func (transformers *ITransformers) AllWithError() func(yield func(*ITransformers, error) bool) {
+	return func(yield func(*ITransformers, error) bool) {
+		iterateWithError(transformers.iterate, func(err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(transformers, nil)
+		})
+	}
+}
+
+// Runs the First/Next loop for All and AllWithError, restoring the previously active transformer.
+func (transformers *ITransformers) iterate(yield func() bool) error {
+	prevIdx, err := transformers.Get_idx()
+	if err != nil {
+		return err
+	}
+	return iterateCollection(transformers.First, transformers.Next, func() error {
+		if prevIdx > 0 {
+			return transformers.Set_idx(prevIdx)
+		}
+		return nil
+	}, yield)
+}
+
 // Active Winding delta or wye connection?
 func (transformers *ITransformers) Get_IsDelta() (bool, error) {
 	return (C.ctx_Transformers_Get_IsDelta(transformers.ctxPtr) != 0), transformers.ctx.DSSError()
@@ -8112,6 +12085,112 @@ func (transformers *ITransformers) Set_kVA(value float64) error {
 	return transformers.ctx.DSSError()
 }
 
+// Runs fn with the winding `wdg` as the active winding, restoring the previously active winding afterwards.
+func (transformers *ITransformers) withWinding(wdg int32, fn func() error) error {
+	prevWdg, err := transformers.Get_Wdg()
+	if err != nil {
+		return err
+	}
+	if err = transformers.Set_Wdg(wdg); err != nil {
+		return err
+	}
+	err = fn()
+	if restoreErr := transformers.Set_Wdg(prevWdg); err == nil {
+		err = restoreErr
+	}
+	return err
+}
+
+// kV rating of the winding `wdg` of the active transformer (see Get_kV).
+// The active winding is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_kVByWinding(wdg int32) (result float64, err error) {
+	err = transformers.withWinding(wdg, func() error {
+		result, err = transformers.Get_kV()
+		return err
+	})
+	return
+}
+
+func (transformers *ITransformers) Set_kVByWinding(wdg int32, value float64) error {
+	return transformers.withWinding(wdg, func() error {
+		return transformers.Set_kV(value)
+	})
+}
+
+// kVA rating of the winding `wdg` of the active transformer (see Get_kVA).
+// The active winding is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_kVAByWinding(wdg int32) (result float64, err error) {
+	err = transformers.withWinding(wdg, func() error {
+		result, err = transformers.Get_kVA()
+		return err
+	})
+	return
+}
+
+func (transformers *ITransformers) Set_kVAByWinding(wdg int32, value float64) error {
+	return transformers.withWinding(wdg, func() error {
+		return transformers.Set_kVA(value)
+	})
+}
+
+// Tap, in per-unit, of the winding `wdg` of the active transformer (see Get_Tap).
+// The active winding is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_TapByWinding(wdg int32) (result float64, err error) {
+	err = transformers.withWinding(wdg, func() error {
+		result, err = transformers.Get_Tap()
+		return err
+	})
+	return
+}
+
+func (transformers *ITransformers) Set_TapByWinding(wdg int32, value float64) error {
+	return transformers.withWinding(wdg, func() error {
+		return transformers.Set_Tap(value)
+	})
+}
+
+// Resistance, in %, of the winding `wdg` of the active transformer (see Get_R).
+// The active winding is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_RByWinding(wdg int32) (result float64, err error) {
+	err = transformers.withWinding(wdg, func() error {
+		result, err = transformers.Get_R()
+		return err
+	})
+	return
+}
+
+func (transformers *ITransformers) Set_RByWinding(wdg int32, value float64) error {
+	return transformers.withWinding(wdg, func() error {
+		return transformers.Set_R(value)
+	})
+}
+
+// Connection, delta (true) or wye, of the winding `wdg` of the active transformer (see Get_IsDelta).
+// The active winding is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_IsDeltaByWinding(wdg int32) (result bool, err error) {
+	err = transformers.withWinding(wdg, func() error {
+		result, err = transformers.Get_IsDelta()
+		return err
+	})
+	return
+}
+
+func (transformers *ITransformers) Set_IsDeltaByWinding(wdg int32, value bool) error {
+	return transformers.withWinding(wdg, func() error {
+		return transformers.Set_IsDelta(value)
+	})
+}
+
 // Complex array of voltages for active winding
 //
 // WARNING: If the transformer has open terminal(s), results may be wrong, i.e. avoid using this
@@ -8174,6 +12253,108 @@ func (transformers *ITransformers) AllLossesByType() ([]complex128, error) {
 	return transformers.ctx.GetComplexArrayGR()
 }
 
+// Returns one of the losses from LossesByType of the active transformer.
+func (transformers *ITransformers) lossByType(idx int) (complex128, error) {
+	losses, err := transformers.LossesByType()
+	if err != nil {
+		return 0, err
+	}
+	if len(losses) <= idx {
+		return 0, errors.New("(DSSError) The transformer losses are not available.")
+	}
+	return losses[idx], nil
+}
+
+// No-load (core) losses of the active transformer from the last solution, in VA.
+//
+// (API Extension)
+func (transformers *ITransformers) CoreLosses() (complex128, error) {
+	return transformers.lossByType(2)
+}
+
+// Load (copper) losses of the active transformer from the last solution, in VA.
+//
+// (API Extension)
+func (transformers *ITransformers) LoadLosses() (complex128, error) {
+	return transformers.lossByType(1)
+}
+
+// Percent load losses at rated load of the active transformer (the "%LoadLoss" property).
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_pctLoadLoss() (float64, error) {
+	name, err := transformers.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return transformers.getElementPropertyFloat("Transformer."+name, "%LoadLoss")
+}
+
+func (transformers *ITransformers) Set_pctLoadLoss(value float64) error {
+	name, err := transformers.Get_Name()
+	if err != nil {
+		return err
+	}
+	return transformers.setElementProperty("Transformer."+name, "%LoadLoss", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Percent no-load losses at rated excitation voltage of the active transformer (the
+// "%NoLoadLoss" property).
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) Get_pctNoLoadLoss() (float64, error) {
+	name, err := transformers.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return transformers.getElementPropertyFloat("Transformer."+name, "%NoLoadLoss")
+}
+
+func (transformers *ITransformers) Set_pctNoLoadLoss(value float64) error {
+	name, err := transformers.Get_Name()
+	if err != nil {
+		return err
+	}
+	return transformers.setElementProperty("Transformer."+name, "%NoLoadLoss", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Name of the RegControl controlling the active transformer, without the class prefix,
+// or an empty string if there is none. If more than one RegControl is attached, the first
+// one is returned.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (transformers *ITransformers) RegControlName() (result string, err error) {
+	name, err := transformers.Get_Name()
+	if err != nil {
+		return "", err
+	}
+	err = transformers.withCktElement("Transformer."+name, func(cktelement *ICktElement) error {
+		numControls, err := cktelement.NumControls()
+		if err != nil {
+			return err
+		}
+		for i := int32(1); i <= numControls; i++ {
+			controller, err := cktelement.Controller(i)
+			if err != nil {
+				return err
+			}
+			sep := strings.Index(controller, ".")
+			if sep >= 0 && strings.EqualFold(controller[:sep], "RegControl") {
+				result = controller[sep+1:]
+				return nil
+			}
+		}
+		return nil
+	})
+	return
+}
+
 type IVsources struct {
 	ICommonData
 }
@@ -8280,6 +12461,84 @@ func (vsources *IVsources) Set_pu(value float64) error {
 	return vsources.ctx.DSSError()
 }
 
+// Complex power, in kVA, for each conductor of the active source, as flowing into
+// the terminals. The power injected into the circuit shows up as negative values.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (vsources *IVsources) Powers() ([]complex128, error) {
+	name, err := vsources.Get_Name()
+	if err != nil {
+		return nil, err
+	}
+	return vsources.elementPowers("Vsource." + name)
+}
+
+// Positive-sequence equivalent impedance of the active source, in ohms, as derived
+// by the engine from the configured source strength (MVAsc, Isc or Z1). Same as Get_Z1.
+//
+// (API Extension)
+func (vsources *IVsources) EquivalentZsc() (complex128, error) {
+	return vsources.Get_Z1()
+}
+
+// Positive-sequence impedance of the active source, in ohms.
+//
+// Setting it defines the source strength by impedance, replacing MVAsc/Isc.
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (vsources *IVsources) Get_Z1() (complex128, error) {
+	return vsources.getImpedance("R1", "X1")
+}
+
+func (vsources *IVsources) Set_Z1(value complex128) error {
+	return vsources.setImpedance("Z1", value)
+}
+
+// Zero-sequence impedance of the active source, in ohms.
+//
+// Setting it defines the source strength by impedance, replacing MVAsc/Isc.
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (vsources *IVsources) Get_Z0() (complex128, error) {
+	return vsources.getImpedance("R0", "X0")
+}
+
+func (vsources *IVsources) Set_Z0(value complex128) error {
+	return vsources.setImpedance("Z0", value)
+}
+
+// Reads an impedance of the active source from its resistance and reactance properties,
+// preserving the active circuit element.
+func (vsources *IVsources) getImpedance(rName string, xName string) (complex128, error) {
+	name, err := vsources.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	r, err := vsources.getElementPropertyFloat("Vsource."+name, rName)
+	if err != nil {
+		return 0, err
+	}
+	x, err := vsources.getElementPropertyFloat("Vsource."+name, xName)
+	if err != nil {
+		return 0, err
+	}
+	return complex(r, x), nil
+}
+
+// Sets a complex impedance property (e.g. "Z1") of the active source, preserving the active
+// circuit element.
+func (vsources *IVsources) setImpedance(propName string, value complex128) error {
+	name, err := vsources.Get_Name()
+	if err != nil {
+		return err
+	}
+	return vsources.setElementProperty("Vsource."+name, propName, fmt.Sprintf("[%g, %g]", real(value), imag(value)))
+}
+
 type IWireData struct {
 	ICommonData
 }
@@ -8594,6 +12853,7 @@ func (ymatrix *IYMatrix) Init(ctx *DSSContextPtrs) {
 	ymatrix.InitCommon(ctx)
 }
 
+// Zeroes the injection current array (see GetIPointer)
 func (ymatrix *IYMatrix) ZeroInjCurr() error {
 	C.ctx_YMatrix_ZeroInjCurr(ymatrix.ctxPtr)
 	return ymatrix.ctx.DSSError()
@@ -8619,6 +12879,8 @@ func (ymatrix *IYMatrix) AddInAuxCurrents(SType int32) error {
 	return ymatrix.ctx.DSSError()
 }
 
+// Flag indicating the system Y matrix must be rebuilt before the next solution. Set it
+// after changing the circuit outside of the usual property edits.
 func (ymatrix *IYMatrix) Get_SystemYChanged() (bool, error) {
 	return (C.ctx_YMatrix_Get_SystemYChanged(ymatrix.ctxPtr) != 0), ymatrix.ctx.DSSError()
 }
@@ -8683,6 +12945,79 @@ func (ymatrix *IYMatrix) Set_Iteration(value int32) error {
 	return ymatrix.ctx.DSSError()
 }
 
+// Returns the system Y matrix in the compressed sparse column (CSC) format, as used by
+// external sparse solvers (e.g. SciPy's csc_matrix or SuiteSparse):
+//
+//   - `nBus`: the order of the matrix (number of nodes, in YNodeOrder);
+//   - `nNZ`: the number of non-zero elements;
+//   - `colPtr`: the column pointers, `nBus + 1` elements;
+//   - `rowIdx`: the row indices of the non-zero elements, `nNZ` elements;
+//   - `values`: the non-zero values, `nNZ` elements.
+//
+// The indices are zero-based. The matrix is factorized by the engine before it is returned,
+// matching the default of the other DSS-Extensions projects. The arrays are copies and
+// remain valid after later solutions.
+//
+// (API Extension)
+func (ymatrix *IYMatrix) GetCompressedYMatrix() (nBus int32, nNZ int32, colPtr []int32, rowIdx []int32, values []complex128, err error) {
+	var nBus_c, nNZ_c C.uint32_t
+	var colPtr_c, rowIdx_c *C.int32_t
+	var values_c *C.double
+	C.ctx_YMatrix_GetCompressedYMatrix(ymatrix.ctxPtr, ToUint16(true), &nBus_c, &nNZ_c, &colPtr_c, &rowIdx_c, &values_c)
+	defer func() {
+		C.DSS_Dispose_PInteger(&colPtr_c)
+		C.DSS_Dispose_PInteger(&rowIdx_c)
+		C.DSS_Dispose_PDouble(&values_c)
+	}()
+	if err = ymatrix.ctx.DSSError(); err != nil {
+		return 0, 0, nil, nil, nil, err
+	}
+	if colPtr_c == nil || rowIdx_c == nil || values_c == nil {
+		return 0, 0, nil, nil, nil, errors.New("(DSSError) The system Y matrix is not available.")
+	}
+	nBus = int32(nBus_c)
+	nNZ = int32(nNZ_c)
+	colPtr = make([]int32, nBus+1)
+	copy(colPtr, unsafe.Slice((*int32)(unsafe.Pointer(colPtr_c)), nBus+1))
+	rowIdx = make([]int32, nNZ)
+	copy(rowIdx, unsafe.Slice((*int32)(unsafe.Pointer(rowIdx_c)), nNZ))
+	values = make([]complex128, nNZ)
+	copy(values, unsafe.Slice((*complex128)(unsafe.Pointer(values_c)), nNZ))
+	return nBus, nNZ, colPtr, rowIdx, values, nil
+}
+
+// Returns a slice backed by the engine's node voltage array, including the ground
+// reference at index 0, i.e. the node voltages in YNodeOrder start at index 1.
+//
+// The slice is only valid until the arrays are reallocated by the engine, e.g. in the
+// next Y matrix rebuild, and must not be retained.
+//
+// (API Extension)
+func (ymatrix *IYMatrix) GetVPointer() ([]complex128, error) {
+	return ymatrix.ctx.nodeVoltagesPtr()
+}
+
+// Returns a slice backed by the engine's injection current array, including the ground
+// reference at index 0, i.e. the currents in YNodeOrder start at index 1. Use ZeroInjCurr,
+// GetSourceInjCurrents and GetPCInjCurr to fill it.
+//
+// The slice is only valid until the arrays are reallocated by the engine, e.g. in the
+// next Y matrix rebuild, and must not be retained.
+//
+// (API Extension)
+func (ymatrix *IYMatrix) GetIPointer() ([]complex128, error) {
+	var iptr *C.double
+	C.ctx_YMatrix_getIpointer(ymatrix.ctxPtr, &iptr)
+	if err := ymatrix.ctx.DSSError(); err != nil {
+		return nil, err
+	}
+	if iptr == nil {
+		return nil, errors.New("(DSSError) The solution arrays are not initialized.")
+	}
+	numNodes := int(C.ctx_Circuit_Get_NumNodes(ymatrix.ctxPtr))
+	return unsafe.Slice((*complex128)(unsafe.Pointer(iptr)), numNodes+1), ymatrix.ctx.DSSError()
+}
+
 type IZIP struct {
 	ICommonData
 }
@@ -9068,10 +13403,60 @@ func (storages *IStorages) Get_State() (int32, error) {
 }
 
 func (storages *IStorages) Set_State(value int32) error {
+	if value < int32(StorageStates_Charging) || value > int32(StorageStates_Discharging) {
+		return fmt.Errorf("(DSSError) Invalid storage state: %d.", value)
+	}
 	C.ctx_Storages_Set_State(storages.ctxPtr, (C.int32_t)(value))
 	return storages.ctx.DSSError()
 }
 
+// Present amount of energy stored in the active storage element, in kWh.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (storages *IStorages) Get_kWhStored() (float64, error) {
+	name, err := storages.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return storages.getElementPropertyFloat("Storage."+name, "kWhStored")
+}
+
+func (storages *IStorages) Set_kWhStored(value float64) error {
+	name, err := storages.Get_Name()
+	if err != nil {
+		return err
+	}
+	return storages.setElementProperty("Storage."+name, "kWhStored", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Rated energy capacity of the active storage element, in kWh.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (storages *IStorages) Get_kWhRated() (float64, error) {
+	name, err := storages.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return storages.getElementPropertyFloat("Storage."+name, "kWhRated")
+}
+
+// Present amount of energy stored in the active storage element, as a percentage of the rated capacity.
+//
+// The active circuit element is preserved.
+//
+// (API Extension)
+func (storages *IStorages) Get_pctStored() (float64, error) {
+	name, err := storages.Get_Name()
+	if err != nil {
+		return 0, err
+	}
+	return storages.getElementPropertyFloat("Storage."+name, "%Stored")
+}
+
 // Array of Names of all Storage energy meter registers
 func (storages *IStorages) RegisterNames() ([]string, error) {
 	var cnt [4]int32
@@ -9086,6 +13471,36 @@ func (storages *IStorages) RegisterValues() ([]float64, error) {
 	return storages.ctx.GetFloat64ArrayGR()
 }
 
+// Register values of the active storage element, keyed by the register names (see RegisterNames).
+//
+// (API Extension)
+func (storages *IStorages) RegisterValuesMap() (map[string]float64, error) {
+	names, err := storages.RegisterNames()
+	if err != nil {
+		return nil, err
+	}
+	values, err := storages.RegisterValues()
+	if err != nil {
+		return nil, err
+	}
+	return registerValuesMap(names, values)
+}
+
+// Value of a single register of the active storage element, by name (case-insensitive), e.g. "kWh".
+//
+// (API Extension)
+func (storages *IStorages) Register(name string) (float64, error) {
+	names, err := storages.RegisterNames()
+	if err != nil {
+		return 0, err
+	}
+	values, err := storages.RegisterValues()
+	if err != nil {
+		return 0, err
+	}
+	return registerValue(names, values, name)
+}
+
 type IDSS struct {
 	ICommonData
 
@@ -9142,10 +13557,74 @@ func (dss *IDSS) Dispose() {
 		return
 	}
 
+	dropEventHandlers(dss.ctxPtr)
 	C.ctx_Dispose(dss.ctxPtr)
 	dss.ctxPtr = nil
 }
 
+// Runs a DSS script from memory, as if compiling a file with the same content located in the
+// current DataPath, i.e. relative paths in the script are resolved from the DataPath and
+// directory changes made by the script are kept. The script runs through Text.CommandBlock;
+// when EarlyAbort is enabled (see IError), the script stops at the first error.
+//
+// (API Extension)
+func (dss *IDSS) CompileString(script string) error {
+	return dss.Text.CommandBlock(script)
+}
+
+// Same as CompileString, but as a Redirect: the DataPath is restored after running the script.
+//
+// (API Extension)
+func (dss *IDSS) RedirectString(script string) error {
+	dataPath, err := dss.Get_DataPath()
+	if err != nil {
+		return err
+	}
+	err = dss.Text.CommandBlock(script)
+	// Restore through the C-API directly: the previous DataPath was accepted by the engine
+	dataPath_c := C.CString(dataPath)
+	C.ctx_DSS_Set_DataPath(dss.ctxPtr, dataPath_c)
+	C.free(unsafe.Pointer(dataPath_c))
+	if restoreErr := dss.ctx.DSSError(); err == nil {
+		err = restoreErr
+	}
+	return err
+}
+
+// Registers fn to be called when the engine raises the event `evt` in this context,
+// e.g. AltDSSEvent_BuildSystemY whenever the system Y matrix is rebuilt.
+// Returns a handle to use with UnregisterEventHandler.
+//
+// The handlers are called synchronously, from the goroutine running the engine call that
+// raised the event (e.g. Solution.Solve), in registration order. Panics in the handlers cannot
+// propagate through the engine, so they are recovered; the first one is returned as a DSSErr
+// (Number 0) by the next API call in this context, e.g. the Solve call that raised the event.
+// The handlers of a context are removed when it is disposed.
+//
+// (API Extension)
+func (dss *IDSS) RegisterEventHandler(evt AltDSSEvent, fn func()) (int, error) {
+	if fn == nil {
+		return 0, errors.New("(DSSError) The event handler cannot be nil.")
+	}
+	return registerEventHandler(dss.ctxPtr, evt, func(AltDSSEvent) { fn() })
+}
+
+// Removes an event handler registered with RegisterEventHandler.
+//
+// (API Extension)
+func (dss *IDSS) UnregisterEventHandler(handle int) error {
+	return unregisterEventHandler(handle)
+}
+
+// Disposes the DSS engine context, as Dispose. Provided to satisfy io.Closer,
+// e.g. `defer dss.Close()` after NewContext.
+//
+// (API Extension)
+func (dss *IDSS) Close() error {
+	dss.Dispose()
+	return nil
+}
+
 // Creates a new DSS engine context.
 // A DSS Context encapsulates most of the global state of the original OpenDSS engine,
 // allowing the user to create multiple instances in the same process. By creating contexts
@@ -9153,22 +13632,232 @@ func (dss *IDSS) Dispose() {
 //
 // (API Extension)
 func (dss *IDSS) NewContext() (*IDSS, error) {
+	return NewContext()
+}
+
+// Creates a new, independent DSS engine context, fully initialized, without requiring an
+// existing IDSS instance. Dispose it with Close (or Dispose) when done.
+//
+// Each context can be used to run an independent circuit, e.g. one context per goroutine.
+// A single context must not be used from more than one goroutine at a time, since the
+// engine state, including the GR buffers used to return arrays, is per context. When
+// running multiple contexts, consider disabling AllowChangeDir, since the working
+// directory is shared by the whole process.
+//
+// (API Extension)
+func NewContext() (*IDSS, error) {
 	newCtxPtr := C.ctx_New()
-	dssNew := &IDSS{}
-	dssNew.Init(newCtxPtr)
 	if newCtxPtr == nil {
-		return dssNew, errors.New("(DSSError) Could not create a new DSS Context")
+		return nil, errors.New("(DSSError) Could not create a new DSS Context")
 	}
+	dssNew := &IDSS{}
+	dssNew.Init(newCtxPtr)
 	return dssNew, nil
 }
 
+// Creates a new circuit, equivalent to the "new circuit.<name>" command, and returns it as the
+// active circuit. Returns nil and the engine error if the circuit could not be created.
 func (dss *IDSS) NewCircuit(name string) (*ICircuit, error) {
 	name_c := C.CString(name)
 	C.ctx_DSS_NewCircuit(dss.ctxPtr, name_c)
 	C.free(unsafe.Pointer(name_c))
-	return &dss.ActiveCircuit, dss.ctx.DSSError()
+	if err := dss.ctx.DSSError(); err != nil {
+		return nil, err
+	}
+	return &dss.ActiveCircuit, nil
+}
+
+// Runs fn with EarlyAbort disabled and returns every engine error reported to the API
+// calls made within it, in order, instead of stopping at the first one. EarlyAbort is
+// restored afterwards, also when fn panics. If fn itself returns an error that was not
+// reported by the engine, it is appended to the list with Number 0.
+//
+// The engine keeps a single error state, so a multi-line script passed in a single call
+// (e.g. Compile or Redirect) reports only its last error. Use CollectScriptErrors to run
+// a script line by line and collect the error of each line.
+//
+// (API Extension)
+func (dss *IDSS) CollectErrors(fn func() error) (collected []DSSErr) {
+	collected = make([]DSSErr, 0)
+	previousEarlyAbort, err := dss.Error.Get_EarlyAbort()
+	if err != nil {
+		return append(collected, DSSErr{0, err.Error()})
+	}
+	if err = dss.Error.Set_EarlyAbort(false); err != nil {
+		return append(collected, DSSErr{0, err.Error()})
+	}
+	previousCollected := dss.ctx.collectedErrors
+	dss.ctx.collectedErrors = &collected
+	defer func() {
+		dss.ctx.collectedErrors = previousCollected
+		dss.Error.Set_EarlyAbort(previousEarlyAbort)
+	}()
+	err = fn()
+	// Catch errors left pending by the last call
+	dss.ctx.DSSError()
+	if err != nil {
+		found := false
+		for _, dssErr := range collected {
+			if dssErr.Error() == err.Error() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			collected = append(collected, DSSErr{0, err.Error()})
+		}
+	}
+	if previousCollected != nil {
+		*previousCollected = append(*previousCollected, collected...)
+	}
+	return collected
+}
+
+// Runs a script line by line, with EarlyAbort disabled, and returns the errors of all the
+// lines, in order. The description of each error is prefixed with its (1-based) line number,
+// e.g. "Line 3: ...". Empty lines and comments, including block comments, are skipped.
+//
+// Errors inside files called by the script (e.g. Redirect) are reported as the error of
+// the calling line; see CollectErrors.
+//
+// (API Extension)
+func (dss *IDSS) CollectScriptErrors(script string) []DSSErr {
+	result := make([]DSSErr, 0)
+	inBlockComment := false
+	for i, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if inBlockComment || strings.HasPrefix(line, "/*") {
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		for _, dssErr := range dss.CollectErrors(func() error { return dss.Text.Set_Command(line) }) {
+			dssErr.Description = fmt.Sprintf("Line %d: %s", i+1, dssErr.Description)
+			result = append(result, dssErr)
+		}
+	}
+	return result
+}
+
+// Creates a new circuit from JSON data, organized using the JSON schema proposed at
+// https://github.com/dss-extensions/AltDSS-Schema, i.e. the counterpart of `ICircuit.ToJSON`.
+// The new circuit becomes the active circuit.
+//
+// The `options` parameter contains bit-flags to toggle specific features; use the same
+// flags used to export the data where relevant, e.g. `DSSJSONFlags_FullNames`.
+// Errors while loading the objects are reported through the Error interface, i.e.
+// returned as the error here. See `Circuit_FromJSON` (C-API) for more.
+//
+// (API Extension)
+func (dss *IDSS) FromJSON(data []byte, options int32) error {
+	data_c := C.CString(string(data))
+	C.ctx_Circuit_FromJSON(dss.ctxPtr, data_c, (C.int32_t)(options))
+	C.free(unsafe.Pointer(data_c))
+	return dss.ctx.DSSError()
+}
+
+// Activates a circuit by its name.
+//
+// Returns an error if no circuit with the name exists; in that case, the previously active
+// circuit remains active.
+//
+// (API Extension)
+func (dss *IDSS) SetActiveCircuit(name string) (*ICircuit, error) {
+	err := dss.Text.Set_Command(fmt.Sprintf("set circuit=\"%s\"", name))
+	if err != nil {
+		return nil, err
+	}
+	activeName, err := dss.ActiveCircuit.Name()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(activeName, name) {
+		return nil, fmt.Errorf("(DSSError) Circuit \"%s\" not found.", name)
+	}
+	return &dss.ActiveCircuit, nil
+}
+
+// Property name and value, as used in IDSS.NewObject
+type PropertyValue struct {
+	Name  string
+	Value string
 }
 
+// Creates a new DSS object of class `className` (e.g. "Load") named `name`, setting the
+// properties from `props`, and returns the new object as the active DSS element.
+//
+// The object is created and edited through the Obj C-API (Obj_New, Obj_SetAsString and
+// Obj_EndEdit) instead of Text commands. The properties are applied in the order given,
+// since the order matters for some properties, e.g. "phases" before "bus1" in a Line, or
+// "windings" before the winding data in a Transformer. An error is returned for unknown
+// classes and properties; if a property fails, the object is kept in the circuit with the
+// properties set before it.
+//
+// (API Extension)
+func (dss *IDSS) NewObject(className string, name string, props []PropertyValue) (*IDSSElement, error) {
+	clsIdx, err := dss.SetActiveClass(className)
+	if err != nil {
+		return nil, err
+	}
+	if clsIdx <= 0 {
+		return nil, fmt.Errorf("(DSSError) Class \"%s\" not found.", className)
+	}
+	name_c := C.CString(name)
+	obj := C.Obj_New(dss.ctxPtr, (C.int32_t)(clsIdx), name_c, ToUint16(true), ToUint16(true))
+	C.free(unsafe.Pointer(name_c))
+	if err = dss.ctx.DSSError(); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("(DSSError) Could not create %s.%s.", className, name)
+	}
+	element := &dss.ActiveCircuit.ActiveDSSElement
+	propNames, err := element.AllPropertyNames()
+	if err != nil {
+		C.Obj_EndEdit(obj, 0)
+		return nil, err
+	}
+	// Property indices are 1-based, in the order of AllPropertyNames
+	indices := make(map[string]int32, len(propNames))
+	for i, propName := range propNames {
+		indices[strings.ToLower(propName)] = int32(i + 1)
+	}
+	for n, prop := range props {
+		idx, found := indices[strings.ToLower(prop.Name)]
+		if !found {
+			C.Obj_EndEdit(obj, (C.int32_t)(n))
+			return nil, fmt.Errorf("(DSSError) Unknown property \"%s\" for %s.%s.", prop.Name, className, name)
+		}
+		value_c := C.CString(prop.Value)
+		C.Obj_SetAsString(obj, (C.int32_t)(idx), value_c)
+		C.free(unsafe.Pointer(value_c))
+		if err = dss.ctx.DSSError(); err != nil {
+			C.Obj_EndEdit(obj, (C.int32_t)(n))
+			return nil, err
+		}
+	}
+	C.Obj_EndEdit(obj, (C.int32_t)(len(props)))
+	if err = dss.ctx.DSSError(); err != nil {
+		return nil, err
+	}
+	return element, nil
+}
+
+// Quotes a property value for use in a DSS command, unless it is already quoted or
+// is an array/matrix in one of the bracket forms accepted by the parser.
+func quotePropertyValue(value string) string {
+	if value == "" {
+		return "\"\""
+	}
+	if strings.ContainsAny(value[:1], "\"'([{") || !strings.ContainsAny(value, " \t,=") {
+		return value
+	}
+	return "\"" + value + "\""
+}
+
+// Clears all circuits and the definitions in the engine, equivalent to the "clear" command.
 func (dss *IDSS) ClearAll() error {
 	C.ctx_DSS_ClearAll(dss.ctxPtr)
 	return dss.ctx.DSSError()
@@ -9206,11 +13895,19 @@ func (dss *IDSS) Classes() ([]string, error) {
 }
 
 // DSS Data File Path.  Default path for reports, etc. from DSS
+//
+// When setting an absolute path, the directory must exist, otherwise an error is returned.
+// Relative and empty paths are passed as-is to the engine, which resolves them.
 func (dss *IDSS) Get_DataPath() (string, error) {
 	return C.GoString(C.ctx_DSS_Get_DataPath(dss.ctxPtr)), dss.ctx.DSSError()
 }
 
 func (dss *IDSS) Set_DataPath(value string) error {
+	if filepath.IsAbs(value) {
+		if info, err := os.Stat(value); err != nil || !info.IsDir() {
+			return fmt.Errorf("(DSSError) Directory \"%s\" not found.", value)
+		}
+	}
 	value_c := C.CString(value)
 	C.ctx_DSS_Set_DataPath(dss.ctxPtr, value_c)
 	C.free(unsafe.Pointer(value_c))
@@ -9390,3 +14087,30 @@ func (dss *IDSS) Set_CompatFlags(value uint32) error {
 	C.ctx_DSS_Set_CompatFlags(dss.ctxPtr, (C.uint32_t)(value))
 	return dss.ctx.DSSError()
 }
+
+// Naming convention of the property names, e.g. as returned by AllPropertyNames and used
+// in the JSON exports. Use DSSPropertyNameStyle_Legacy to get the older capitalization.
+//
+// The engine does not expose the current style, so the getter is cached on the Go side: it
+// only reflects the last Set_PropertyNameStyle call on this IDSS (Modern if there was none).
+// Changes made by other means, e.g. through another binding sharing the same engine context,
+// are not seen.
+//
+// Related enumeration: DSSPropertyNameStyle
+//
+// (API Extension)
+func (dss *IDSS) Get_PropertyNameStyle() (DSSPropertyNameStyle, error) {
+	return dss.ctx.propertyNameStyle, nil
+}
+
+func (dss *IDSS) Set_PropertyNameStyle(value DSSPropertyNameStyle) error {
+	if value < DSSPropertyNameStyle_Modern || value > DSSPropertyNameStyle_Legacy {
+		return fmt.Errorf("(DSSError) Invalid property name style: %d.", value)
+	}
+	C.ctx_Settings_SetPropertyNameStyle(dss.ctxPtr, (C.int32_t)(value))
+	if err := dss.ctx.DSSError(); err != nil {
+		return err
+	}
+	dss.ctx.propertyNameStyle = value
+	return nil
+}
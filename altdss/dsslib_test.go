@@ -0,0 +1,1754 @@
+package altdss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// A small 3-phase circuit: a source, a line and a balanced load.
+const testCircuitScript = `
+clear
+new circuit.test basekv=12.47 pu=1.0 phases=3 bus1=src
+new line.l1 bus1=src bus2=b2 phases=3 length=1 units=km
+new load.ld1 bus1=b2 phases=3 conn=wye kv=12.47 kw=1000 kvar=300
+set voltagebases=[12.47]
+calcvoltagebases
+`
+
+// Creates a new DSS context, disposed at the end of the test, and runs `script` in it.
+func newTestContext(t *testing.T, script string) *IDSS {
+	t.Helper()
+	dss, err := NewContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		dss.Close()
+	})
+	if err = dss.CompileString(script); err != nil {
+		t.Fatal(err)
+	}
+	return dss
+}
+
+func TestBusLineToLineVoltages(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	if err := dss.ActiveCircuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	bus, err := dss.ActiveCircuit.ActivateBus("b2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vll, err := bus.LineToLineVoltages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vll) != 3 {
+		t.Fatalf("expected 3 L-L voltages, got %d: %v", len(vll), vll)
+	}
+	for _, label := range []string{"AB", "BC", "CA"} {
+		v, ok := vll[label]
+		if !ok {
+			t.Fatalf("missing L-L voltage %q in %v", label, vll)
+		}
+		// The load is light, so the L-L voltages stay close to the 12.47 kV base
+		if mag := cmplx.Abs(v); mag < 0.9*12470 || mag > 1.1*12470 {
+			t.Errorf("unexpected magnitude for %s: %g V", label, mag)
+		}
+	}
+}
+
+func TestTimeSeriesCollectorDaily(t *testing.T) {
+	mult := strings.TrimSpace(strings.Repeat("0.5 0.8 1.0 0.7 ", 6))
+	dss := newTestContext(t, testCircuitScript+`
+new loadshape.ls1 npts=24 interval=1 mult=(`+mult+`)
+edit load.ld1 daily=ls1
+`)
+	solution := &dss.ActiveCircuit.Solution
+	if err := solution.Set_Mode(SolveModes_Daily); err != nil {
+		t.Fatal(err)
+	}
+	collector := NewTimeSeriesCollector(&dss.ActiveCircuit)
+	if err := collector.AddNode("b2.1", TimeSeriesQuantity_VoltagePU); err != nil {
+		t.Fatal(err)
+	}
+	if err := collector.AddElement("Line.l1", TimeSeriesQuantity_kW); err != nil {
+		t.Fatal(err)
+	}
+	if err := solution.SolveSteps(24, collector.Record); err != nil {
+		t.Fatal(err)
+	}
+	result := collector.Result()
+	if len(result.Hours) != 24 {
+		t.Fatalf("expected 24 steps, got %d", len(result.Hours))
+	}
+	for i, series := range result.Series {
+		if len(series) != 24 {
+			t.Errorf("expected 24 points for %s, got %d", result.Names[i], len(series))
+		}
+	}
+	for i := 1; i < len(result.Hours); i++ {
+		if result.Hours[i] <= result.Hours[i-1] {
+			t.Fatalf("the solution time did not advance at step %d: %v", i, result.Hours)
+		}
+	}
+}
+
+func TestSetActiveCircuitNotFound(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	if _, err := dss.SetActiveCircuit("bogus"); err == nil {
+		t.Fatal("expected an error for a circuit that does not exist")
+	}
+	name, err := dss.ActiveCircuit.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, "test") {
+		t.Fatalf("expected the circuit \"test\" to remain active, got %q", name)
+	}
+	circuit, err := dss.SetActiveCircuit("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if circuit != &dss.ActiveCircuit {
+		t.Fatal("expected the ActiveCircuit interface")
+	}
+}
+
+func TestSolutionSaveLoadState(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new loadshape.day npts=24 interval=1 mult=[0.3, 0.3, 0.3, 0.35, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95, 1, 1, 0.95, 0.9, 0.9, 0.95, 1, 0.95, 0.85, 0.7, 0.55, 0.45, 0.35]
+edit load.ld1 daily=day
+set mode=daily stepsize=1h number=1
+`)
+	circuit := &dss.ActiveCircuit
+	solution := &circuit.Solution
+	// Runs `steps` time steps, returning the hour and node voltages after the last one
+	run := func(steps int) (float64, []complex128) {
+		t.Helper()
+		for i := 0; i < steps; i++ {
+			if err := solution.Solve(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		hour, err := solution.Get_dblHour()
+		if err != nil {
+			t.Fatal(err)
+		}
+		voltages, err := circuit.YNodeVarray()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hour, voltages
+	}
+
+	run(6)
+	state, err := solution.SaveState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHour, expected := run(3)
+
+	// Perturb the run: a different load multiplier and more time steps
+	if err = solution.Set_LoadMult(1.5); err != nil {
+		t.Fatal(err)
+	}
+	if _, perturbed := run(5); cmplx.Abs(perturbed[len(perturbed)-1]-expected[len(expected)-1]) < 1e-3 {
+		t.Fatal("expected the voltages to change with the perturbation")
+	}
+
+	// Resuming from the saved state must reproduce the unperturbed run
+	if err = solution.LoadState(state); err != nil {
+		t.Fatal(err)
+	}
+	hour, voltages := run(3)
+	if hour != expectedHour {
+		t.Errorf("expected the resumed run to reach hour %g, got %g", expectedHour, hour)
+	}
+	if len(voltages) != len(expected) {
+		t.Fatalf("expected %d node voltages, got %d", len(expected), len(voltages))
+	}
+	for i := range expected {
+		if cmplx.Abs(voltages[i]-expected[i]) > 1e-6*cmplx.Abs(expected[i]) {
+			t.Errorf("node voltage %d differs from the unperturbed run: expected %v, got %v", i, expected[i], voltages[i])
+		}
+	}
+	loadMult, err := solution.Get_LoadMult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadMult != 1 {
+		t.Errorf("expected the load multiplier to be restored to 1, got %g", loadMult)
+	}
+}
+
+func TestCollectScriptErrors(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	earlyAbort, err := dss.Error.Get_EarlyAbort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := dss.CollectScriptErrors(`new line.l2 bus1=b2 bus2=b3 phases=3 length=1 units=km
+bogus_command_one
+! a comment
+bogus_command_two
+new load.ld2 bus1=b3 phases=3 kv=12.47 kw=100`)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+	for i, prefix := range []string{"Line 2: ", "Line 4: "} {
+		if !strings.HasPrefix(errs[i].Description, prefix) {
+			t.Errorf("expected error %d to start with %q, got %q", i, prefix, errs[i].Description)
+		}
+	}
+	// The valid lines after the errors must have run
+	if _, err = dss.ActiveCircuit.SetActiveElement("Load.ld2"); err != nil {
+		t.Fatal(err)
+	}
+	name, err := dss.ActiveCircuit.ActiveCktElement.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, "Load.ld2") {
+		t.Errorf("expected Load.ld2 to be created, got active element %q", name)
+	}
+	restored, err := dss.Error.Get_EarlyAbort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != earlyAbort {
+		t.Errorf("expected EarlyAbort to be restored to %v", earlyAbort)
+	}
+}
+
+func TestCircuitSaveScript(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	var buf bytes.Buffer
+	if err := dss.ActiveCircuit.SaveScript(&buf, DSSSaveFlags_CalcVoltageBases); err != nil {
+		t.Fatal(err)
+	}
+	script := buf.String()
+	lower := strings.ToLower(script)
+	for _, expected := range []string{"new circuit.test", "line.l1", "load.ld1", "calcvoltagebases"} {
+		if !strings.Contains(lower, expected) {
+			t.Errorf("expected %q in the saved script:\n%s", expected, script)
+		}
+	}
+
+	// The saved script must recreate the same circuit
+	names, err := dss.ActiveCircuit.AllElementNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := newTestContext(t, script)
+	copiedNames, err := copied.ActiveCircuit.AllElementNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sortedLower(copiedNames) != sortedLower(names) {
+		t.Errorf("expected the elements %v, got %v", names, copiedNames)
+	}
+}
+
+// Returns the names sorted and lowercased, joined by commas, to compare lists of DSS names.
+func sortedLower(names []string) string {
+	lower := make([]string, len(names))
+	for i, name := range names {
+		lower[i] = strings.ToLower(name)
+	}
+	sort.Strings(lower)
+	return strings.Join(lower, ",")
+}
+
+func TestNewObject(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new load.ld_text bus1=b2 phases=3 conn=wye kv=12.47 kw=500 kvar=100
+`)
+	element, err := dss.NewObject("Load", "ld_obj", []PropertyValue{
+		{"bus1", "b2"},
+		{"phases", "3"},
+		{"conn", "wye"},
+		{"kv", "12.47"},
+		{"kw", "500"},
+		{"kvar", "100"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := element.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, "Load.ld_obj") {
+		t.Fatalf("expected the new load to be active, got %q", name)
+	}
+	if err = dss.ActiveCircuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	type loadData struct {
+		kW, kvar, kV float64
+		phases       int32
+		powers       []complex128
+	}
+	read := func(loadName string) (data loadData) {
+		t.Helper()
+		loads := &dss.ActiveCircuit.Loads
+		if err := loads.Set_Name(loadName); err != nil {
+			t.Fatal(err)
+		}
+		var err error
+		if data.kW, err = loads.Get_kW(); err != nil {
+			t.Fatal(err)
+		}
+		if data.kvar, err = loads.Get_kvar(); err != nil {
+			t.Fatal(err)
+		}
+		if data.kV, err = loads.Get_kV(); err != nil {
+			t.Fatal(err)
+		}
+		if data.phases, err = loads.Get_Phases(); err != nil {
+			t.Fatal(err)
+		}
+		if data.powers, err = loads.Powers(); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	fromText, fromObj := read("ld_text"), read("ld_obj")
+	if fromObj.kW != fromText.kW || fromObj.kvar != fromText.kvar || fromObj.kV != fromText.kV || fromObj.phases != fromText.phases {
+		t.Errorf("expected %+v, got %+v", fromText, fromObj)
+	}
+	if len(fromObj.powers) != len(fromText.powers) {
+		t.Fatalf("expected %d powers, got %d", len(fromText.powers), len(fromObj.powers))
+	}
+	for i := range fromText.powers {
+		if cmplx.Abs(fromObj.powers[i]-fromText.powers[i]) > 1e-6 {
+			t.Errorf("power %d: expected %v, got %v", i, fromText.powers[i], fromObj.powers[i])
+		}
+	}
+
+	if _, err = dss.NewObject("Load", "ld_bad", []PropertyValue{{"no_such_property", "1"}}); err == nil {
+		t.Error("expected an error for an unknown property")
+	}
+}
+
+func TestCircuitSetSeason(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.lr bus1=b2 bus2=b3 phases=3 length=1 units=km normamps=400 seasons=3 ratings=[400 300 200]
+`)
+	lines := &dss.ActiveCircuit.Lines
+	for season, expected := range []float64{400, 300, 200} {
+		if err := dss.ActiveCircuit.SetSeason(int32(season)); err != nil {
+			t.Fatal(err)
+		}
+		if err := lines.Set_Name("lr"); err != nil {
+			t.Fatal(err)
+		}
+		rating, err := lines.SeasonRating()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rating != expected {
+			t.Errorf("season %d: expected a rating of %g A, got %g A", season, expected, rating)
+		}
+	}
+
+	if err := dss.ActiveCircuit.SetSeason(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := dss.ActiveCircuit.ClearSeason(); err != nil {
+		t.Fatal(err)
+	}
+	if err := lines.Set_Name("lr"); err != nil {
+		t.Fatal(err)
+	}
+	rating, err := lines.SeasonRating()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rating != 400 {
+		t.Errorf("expected the normal rating after ClearSeason, got %g A", rating)
+	}
+}
+
+func TestEventHandlerPanic(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	if _, err := dss.RegisterEventHandler(AltDSSEvent_BuildSystemY, func() {
+		panic("handler bug")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	err := dss.ActiveCircuit.Solution.Solve()
+	if err == nil || !strings.Contains(err.Error(), "handler bug") {
+		t.Fatalf("expected the panic to be reported, got %v", err)
+	}
+	// Reported once
+	if err = dss.ActiveCircuit.Solution.Solve(); err != nil && strings.Contains(err.Error(), "handler bug") {
+		t.Fatalf("expected the panic to be reported only once, got %v", err)
+	}
+}
+
+func TestSolutionProgressCallback(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	solution := &dss.ActiveCircuit.Solution
+	if err := solution.Set_Mode(SolveModes_Daily); err != nil {
+		t.Fatal(err)
+	}
+	if err := solution.Set_Number(24); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	solution.SetProgressCallback(func(pct float64, hour int32) bool {
+		calls++
+		return calls < 5
+	})
+	// Solve does not report progress
+	if err := solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected Solve not to call the progress callback, got %d calls", calls)
+	}
+	if err := solution.SolveContext(context.Background()); err == nil {
+		t.Fatal("expected an error when the callback aborts the solution")
+	}
+	if calls != 5 {
+		t.Errorf("expected the solution to stop after 5 steps, got %d calls", calls)
+	}
+}
+
+// Fails the test if the active circuit element is not `fullName`.
+func checkActiveElement(t *testing.T, dss *IDSS, fullName string) {
+	t.Helper()
+	name, err := dss.ActiveCircuit.ActiveCktElement.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, fullName) {
+		t.Errorf("expected the active circuit element to be %s, got %s", fullName, name)
+	}
+}
+
+// Activates the circuit element `fullName`, failing the test if it is not found.
+func activateElement(t *testing.T, dss *IDSS, fullName string) {
+	t.Helper()
+	idx, err := dss.ActiveCircuit.SetActiveElement(fullName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx < 0 {
+		t.Fatalf("element %s not found", fullName)
+	}
+}
+
+func TestStoragesEnergy(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new storage.s1 bus1=b2 phases=3 kv=12.47 kwrated=100 kwhrated=400 %stored=50
+`)
+	storages := &dss.ActiveCircuit.Storages
+	if err := storages.Set_Name("s1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	rated, err := storages.Get_kWhRated()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := storages.Get_kWhStored()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rated != 400 || stored != 200 {
+		t.Errorf("expected 400 kWh rated and 200 kWh stored, got %g and %g", rated, stored)
+	}
+	if err = storages.Set_kWhStored(100); err != nil {
+		t.Fatal(err)
+	}
+	pct, err := storages.Get_pctStored()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pct != 25 {
+		t.Errorf("expected 25%% stored, got %g", pct)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestVsourcesImpedance(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	vsources := &dss.ActiveCircuit.Vsources
+	if err := vsources.Set_Name("source"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	if err := vsources.Set_Z1(complex(0.5, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vsources.Set_Z0(complex(1, 6)); err != nil {
+		t.Fatal(err)
+	}
+	z1, err := vsources.Get_Z1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	z0, err := vsources.Get_Z0()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmplx.Abs(z1-complex(0.5, 4)) > 1e-9 || cmplx.Abs(z0-complex(1, 6)) > 1e-9 {
+		t.Errorf("expected Z1=(0.5+4i) and Z0=(1+6i), got %v and %v", z1, z0)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestPVSystemsLimits(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new pvsystem.pv1 bus1=b2 phases=3 kv=12.47 kva=500 pmpp=450
+`)
+	pvsystems := &dss.ActiveCircuit.PVSystems
+	if err := pvsystems.Set_Name("pv1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	if err := pvsystems.Set_kvarLimit(200); err != nil {
+		t.Fatal(err)
+	}
+	if err := pvsystems.Set_Vmaxpu(1.08); err != nil {
+		t.Fatal(err)
+	}
+	kvarLimit, err := pvsystems.Get_kvarLimit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmaxpu, err := pvsystems.Get_Vmaxpu()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kvarLimit != 200 || vmaxpu != 1.08 {
+		t.Errorf("expected kvarLimit=200 and Vmaxpu=1.08, got %g and %g", kvarLimit, vmaxpu)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestTransformersLossPercentages(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new transformer.t1 phases=3 windings=2 buses=[b2, b3] kvs=[12.47, 0.48] kvas=[500, 500]
+`)
+	transformers := &dss.ActiveCircuit.Transformers
+	if err := transformers.Set_Name("t1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	if err := transformers.Set_pctLoadLoss(1.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := transformers.Set_pctNoLoadLoss(0.25); err != nil {
+		t.Fatal(err)
+	}
+	loadLoss, err := transformers.Get_pctLoadLoss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	noLoadLoss, err := transformers.Get_pctNoLoadLoss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadLoss != 1.5 || noLoadLoss != 0.25 {
+		t.Errorf("expected %%LoadLoss=1.5 and %%NoLoadLoss=0.25, got %g and %g", loadLoss, noLoadLoss)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestCapacitorsKvarPerStep(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new capacitor.c1 bus1=b2 phases=3 kv=12.47 kvar=600
+`)
+	capacitors := &dss.ActiveCircuit.Capacitors
+	if err := capacitors.Set_Name("c1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	if err := capacitors.Set_kvarPerStep([]float64{300, 150, 150}); err != nil {
+		t.Fatal(err)
+	}
+	kvar, err := capacitors.Get_kvarPerStep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	numSteps, err := capacitors.Get_NumSteps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numSteps != 3 || len(kvar) != 3 || kvar[0] != 300 || kvar[1] != 150 || kvar[2] != 150 {
+		t.Errorf("expected 3 steps of [300 150 150] kvar, got %d steps of %v", numSteps, kvar)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestLinesRatings(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	lines := &dss.ActiveCircuit.Lines
+	if err := lines.Set_Name("l1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Load.ld1")
+	if err := lines.Set_Ratings([]float64{400, 450}); err != nil {
+		t.Fatal(err)
+	}
+	ratings, err := lines.Get_Ratings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	numRatings, err := lines.Get_NumAmpRatings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numRatings != 2 || len(ratings) != 2 || ratings[0] != 400 || ratings[1] != 450 {
+		t.Errorf("expected 2 ratings of [400 450] A, got %d ratings of %v", numRatings, ratings)
+	}
+	checkActiveElement(t, dss, "Load.ld1")
+}
+
+func TestLoadsVoltageBoundsReport(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new load.ld2 bus1=b2 phases=3 kv=12.47 kw=500 kvar=100 vminpu=0.9 vmaxpu=1.1
+`)
+	loads := &dss.ActiveCircuit.Loads
+	if err := loads.Set_Name("ld2"); err != nil {
+		t.Fatal(err)
+	}
+	report, err := loads.VoltageBoundsReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 2 || report["ld2"] != [2]float64{0.9, 1.1} || report["ld1"] != [2]float64{0.95, 1.05} {
+		t.Errorf("unexpected voltage bounds: %v", report)
+	}
+	name, err := loads.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "ld2" {
+		t.Errorf("expected the active load to be ld2, got %s", name)
+	}
+}
+
+func TestCircuitShuntCompensation(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 length=1 units=km
+new capacitor.c1 bus1=b2 phases=3 kv=12.47 kvar=600
+new capacitor.c2 bus1=b3 phases=3 kv=12.47 kvar=[300, 300] numsteps=2 states=[1, 0]
+new reactor.r1 bus1=b2 phases=3 kv=12.47 kvar=200
+new reactor.r2 bus1=b2 bus2=b3 phases=3 kv=12.47 kvar=50
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Capacitors.Set_Name("c1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := circuit.Reactors.Set_Name("r1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	capacitiveMvar, inductiveMvar, err := circuit.ShuntCompensation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(capacitiveMvar-0.9) > 1e-9 || math.Abs(inductiveMvar-0.2) > 1e-9 {
+		t.Errorf("expected 0.9 Mvar capacitive and 0.2 Mvar inductive, got %g and %g", capacitiveMvar, inductiveMvar)
+	}
+	capacitor, err := circuit.Capacitors.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reactor, err := circuit.Reactors.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capacitor != "c1" || reactor != "r1" {
+		t.Errorf("expected the active capacitor and reactor to be c1 and r1, got %s and %s", capacitor, reactor)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestCircuitApplyEdits(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	circuit := &dss.ActiveCircuit
+	checkValues := func(kW, length float64) {
+		t.Helper()
+		if err := circuit.Loads.Set_Name("ld1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := circuit.Lines.Set_Name("l1"); err != nil {
+			t.Fatal(err)
+		}
+		gotkW, err := circuit.Loads.Get_kW()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotLength, err := circuit.Lines.Get_Length()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotkW != kW || gotLength != length {
+			t.Errorf("expected kW=%g and length=%g, got %g and %g", kW, length, gotkW, gotLength)
+		}
+	}
+
+	rollback, err := circuit.ApplyEdits([]Edit{
+		{"Load.ld1", "kW", "500"},
+		{"Line.l1", "Length", "2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkValues(500, 2)
+	if err = circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if err = rollback(); err != nil {
+		t.Fatal(err)
+	}
+	checkValues(1000, 1)
+
+	_, err = circuit.ApplyEdits([]Edit{
+		{"Load.ld1", "kW", "700"},
+		{"Load.missing", "kW", "1"},
+	})
+	if err == nil {
+		t.Fatal("expected an error editing a missing element")
+	}
+	checkValues(1000, 1)
+}
+
+func TestLinesAllWithError(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 length=1 units=km
+new line.l3 bus1=b3 bus2=b4 length=1 units=km
+`)
+	lines := &dss.ActiveCircuit.Lines
+	if err := lines.Set_Name("l3"); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	lines.AllWithError()(func(elem *ILines, err error) bool {
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, err := elem.Get_Name()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+		return len(names) < 2
+	})
+	if strings.Join(names, ",") != "l1,l2" {
+		t.Errorf("expected to stop after l1 and l2, got %v", names)
+	}
+	name, err := lines.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "l3" {
+		t.Errorf("expected the active line to be l3, got %s", name)
+	}
+
+	// Without a circuit, the error is yielded instead of ending the loop silently
+	empty := newTestContext(t, "clear")
+	var errs []error
+	empty.ActiveCircuit.Lines.AllWithError()(func(elem *ILines, err error) bool {
+		if err == nil {
+			t.Error("expected no elements without a circuit")
+		}
+		errs = append(errs, err)
+		return true
+	})
+	if len(errs) != 1 {
+		t.Errorf("expected a single error, got %v", errs)
+	}
+}
+
+func TestPropertyNameStyle(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	style, err := dss.Get_PropertyNameStyle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if style != DSSPropertyNameStyle_Modern {
+		t.Errorf("expected the Modern style by default, got %d", style)
+	}
+	if err = dss.Set_PropertyNameStyle(DSSPropertyNameStyle_Lowercase); err != nil {
+		t.Fatal(err)
+	}
+	if style, err = dss.Get_PropertyNameStyle(); err != nil {
+		t.Fatal(err)
+	}
+	if style != DSSPropertyNameStyle_Lowercase {
+		t.Errorf("expected the Lowercase style, got %d", style)
+	}
+	if _, err = dss.ActiveCircuit.SetActiveElement("Line.l1"); err != nil {
+		t.Fatal(err)
+	}
+	names, err := dss.ActiveCircuit.ActiveCktElement.AllPropertyNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if name != strings.ToLower(name) {
+			t.Errorf("expected lowercase property names, got %s", name)
+			break
+		}
+	}
+	if err = dss.Set_PropertyNameStyle(DSSPropertyNameStyle(3)); err == nil {
+		t.Error("expected an error for an invalid style")
+	}
+}
+
+func TestLoadShapesStats(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new loadshape.ponly npts=1 interval=1 mult=[0.7]
+new loadshape.pq npts=1 interval=1 mult=[0.7] qmult=[0.3]
+new loadshape.daily npts=3 interval=1 mult=[0.5, 1, 0.75] qmult=[0.1, 0.4, 0.2]
+`)
+	loadshapes := &dss.ActiveCircuit.LoadShapes
+	if err := loadshapes.Set_Name("ponly"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadshapes.Get_MaxQ(); err == nil {
+		t.Error("expected an error for a one-point shape without Q multipliers")
+	}
+
+	if err := loadshapes.Set_Name("pq"); err != nil {
+		t.Fatal(err)
+	}
+	maxQ, err := loadshapes.Get_MaxQ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxQ != 0.3 {
+		t.Errorf("expected MaxQ=0.3 for a one-point shape, got %g", maxQ)
+	}
+
+	if err = loadshapes.Set_Name("daily"); err != nil {
+		t.Fatal(err)
+	}
+	maxP, err := loadshapes.Get_MaxP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	meanP, err := loadshapes.Get_MeanP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	meanQ, err := loadshapes.Get_MeanQ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxP != 1 || math.Abs(meanP-0.75) > 1e-12 || math.Abs(meanQ-0.7/3) > 1e-12 {
+		t.Errorf("expected MaxP=1, MeanP=0.75 and MeanQ=%g, got %g, %g and %g", 0.7/3, maxP, meanP, meanQ)
+	}
+}
+
+func TestLoadShapesAppendPoint(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new loadshape.fixed npts=2 interval=1 mult=[0.5, 0.6] qmult=[0.1, 0.2]
+new loadshape.variable npts=2 interval=0 mult=[0.5, 0.6] hour=[0, 1]
+new loadshape.ponly npts=1 interval=1 mult=[0.7]
+`)
+	loadshapes := &dss.ActiveCircuit.LoadShapes
+	checkArray := func(what string, got []float64, err error, expected ...float64) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("expected %s=%v, got %v", what, expected, got)
+		}
+		for i := range got {
+			if math.Abs(got[i]-expected[i]) > 1e-12 {
+				t.Fatalf("expected %s=%v, got %v", what, expected, got)
+			}
+		}
+	}
+
+	if err := loadshapes.Set_Name("fixed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadshapes.AppendPoint(0.7, 0.3, 99); err != nil {
+		t.Fatal(err)
+	}
+	pmult, err := loadshapes.Get_Pmult()
+	checkArray("Pmult", pmult, err, 0.5, 0.6, 0.7)
+	qmult, err := loadshapes.Get_Qmult()
+	checkArray("Qmult", qmult, err, 0.1, 0.2, 0.3)
+
+	// Variable interval (HrInterval=0): the time value is appended too
+	if err = loadshapes.Set_Name("variable"); err != nil {
+		t.Fatal(err)
+	}
+	if err = loadshapes.AppendPoint(0.8, 0, 2.5); err != nil {
+		t.Fatal(err)
+	}
+	pmult, err = loadshapes.Get_Pmult()
+	checkArray("Pmult", pmult, err, 0.5, 0.6, 0.8)
+	timeArray, err := loadshapes.Get_TimeArray()
+	checkArray("TimeArray", timeArray, err, 0, 1, 2.5)
+	if err = loadshapes.AppendPoint(0.9, 0.1, 3); err == nil {
+		t.Error("expected an error appending a Q value to a shape without Q multipliers")
+	}
+
+	// A one-point shape without Q data must not be taken as having one Q multiplier
+	if err = loadshapes.Set_Name("ponly"); err != nil {
+		t.Fatal(err)
+	}
+	if err = loadshapes.AppendPoint(0.8, 0.2, 0); err == nil {
+		t.Error("expected an error appending a Q value to a one-point shape without Q multipliers")
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	earlyAbort, err := dss.Error.Get_EarlyAbort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inner []DSSErr
+	outer := dss.CollectErrors(func() error {
+		dss.Text.Set_Command("bogus_command_one")
+		inner = dss.CollectErrors(func() error {
+			return dss.Text.Set_Command("bogus_command_two")
+		})
+		if err := dss.Text.Set_Command("new load.ld2 bus1=b2 phases=3 kv=12.47 kw=100"); err != nil {
+			return err
+		}
+		return dss.Text.Set_Command("bogus_command_three")
+	})
+	if len(inner) != 1 || !strings.Contains(inner[0].Description, "bogus_command_two") {
+		t.Errorf("expected the error of the nested call only, got %v", inner)
+	}
+	// The errors of the nested call are also reported to the outer one, in order
+	if len(outer) != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", len(outer), outer)
+	}
+	for i, command := range []string{"bogus_command_one", "bogus_command_two", "bogus_command_three"} {
+		if !strings.Contains(outer[i].Description, command) {
+			t.Errorf("expected error %d to mention %s, got %q", i, command, outer[i].Description)
+		}
+	}
+	restored, err := dss.Error.Get_EarlyAbort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != earlyAbort {
+		t.Errorf("expected EarlyAbort to be restored to %v", earlyAbort)
+	}
+}
+
+func TestMonitorsPhaseChannels(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new monitor.m1 element=line.l1 terminal=1 mode=0
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	monitors := &circuit.Monitors
+	if err := monitors.SampleAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitors.SaveAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitors.Set_Name("m1"); err != nil {
+		t.Fatal(err)
+	}
+	voltages, err := monitors.VoltageChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	currents, err := monitors.CurrentChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voltages) != 3 || len(currents) != 3 {
+		t.Fatalf("expected 3 voltage and 3 current channels, got %d and %d", len(voltages), len(currents))
+	}
+	// The first channel is V1; the decoded data must match the engine's Channel
+	v1, err := monitors.Channel(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voltages[0]) != 1 || len(v1) != 1 || math.Abs(voltages[0][0]-v1[0]) > 1e-3 {
+		t.Errorf("expected V1=%v, got %v", v1, voltages[0])
+	}
+	for i := range voltages {
+		if len(voltages[i]) != 1 || math.Abs(voltages[i][0]-12470/math.Sqrt(3)) > 10 {
+			t.Errorf("expected the source voltage in channel V%d, got %v", i+1, voltages[i])
+		}
+		if len(currents[i]) != 1 || currents[i][0] <= 0 {
+			t.Errorf("expected a load current in channel I%d, got %v", i+1, currents[i])
+		}
+	}
+}
+
+func TestCircuitBusCoords(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	circuit := &dss.ActiveCircuit
+	if _, err := circuit.SetActiveBus("b2"); err != nil {
+		t.Fatal(err)
+	}
+	err := circuit.SetBusCoords([]string{"src", "B2", "missing"}, []float64{0, 10, 20}, []float64{1, 2, 3})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error for the missing bus, got %v", err)
+	}
+	names, x, y, err := circuit.GetBusCoords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "src,b2" || len(x) != 2 || len(y) != 2 || x[0] != 0 || x[1] != 10 || y[0] != 1 || y[1] != 2 {
+		t.Errorf("unexpected bus coordinates: %v %v %v", names, x, y)
+	}
+	name, err := circuit.ActiveBus.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "b2" {
+		t.Errorf("expected the active bus to be b2, got %s", name)
+	}
+}
+
+func TestMonitorsResidual(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new load.ld2 bus1=b2.1 phases=1 kv=7.2 kw=300 kvar=0
+new monitor.plain element=line.l1 terminal=1 mode=0
+`)
+	circuit := &dss.ActiveCircuit
+	monitors := &circuit.Monitors
+	if err := monitors.NewResidual("Line.l1", 1); err != nil {
+		t.Fatal(err)
+	}
+	name, err := monitors.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, "residual_Line_l1_1") {
+		t.Errorf("expected the new monitor to be active, got %s", name)
+	}
+	if err = circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if err = monitors.SampleAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err = monitors.SaveAll(); err != nil {
+		t.Fatal(err)
+	}
+	residual, err := monitors.ResidualChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The single-phase load draws about 300 kW / 7.2 kV, returning through the neutral
+	if len(residual) != 1 || residual[0] < 30 || residual[0] > 50 {
+		t.Errorf("expected a residual current of about 42 A, got %v", residual)
+	}
+	if err = monitors.Set_Name("plain"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = monitors.ResidualChannel(); err == nil {
+		t.Error("expected an error for a monitor without residual channels")
+	}
+}
+
+func TestLineCodesCloneTo(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new linecode.lc1 nphases=3 r1=0.1 x1=0.3 r0=0.4 x0=1.2 c1=3.4 c0=1.6 units=km normamps=400 emergamps=600
+`)
+	linecodes := &dss.ActiveCircuit.LineCodes
+	// Reads the properties compared between the source and the clone
+	props := func() []float64 {
+		t.Helper()
+		getters := []func() (float64, error){
+			linecodes.Get_R1, linecodes.Get_X1, linecodes.Get_R0, linecodes.Get_X0,
+			linecodes.Get_C1, linecodes.Get_C0, linecodes.Get_NormAmps, linecodes.Get_EmergAmps,
+		}
+		result := make([]float64, 0, len(getters)+2)
+		for _, get := range getters {
+			value, err := get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = append(result, value)
+		}
+		phases, err := linecodes.Get_Phases()
+		if err != nil {
+			t.Fatal(err)
+		}
+		units, err := linecodes.Get_Units()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(result, float64(phases), float64(units))
+	}
+
+	if err := linecodes.Set_Name("lc1"); err != nil {
+		t.Fatal(err)
+	}
+	expected := props()
+	if err := linecodes.CloneTo("lc2"); err != nil {
+		t.Fatal(err)
+	}
+	name, err := linecodes.Get_Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "lc2" {
+		t.Fatalf("expected the clone to be active, got %s", name)
+	}
+	got := props()
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected the clone to match the source, got %v instead of %v", got, expected)
+			break
+		}
+	}
+}
+
+func TestCircuitCapacityReport(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new energymeter.m1 element=line.l1 terminal=1
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Set_LoadMult(0.8); err != nil {
+		t.Fatal(err)
+	}
+	capacityFactor, addedMW, _, err := circuit.CapacityReport(0.5, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capacityFactor <= 0.8 || addedMW <= 0 {
+		t.Errorf("expected capacity above the present load, got factor %g and %g MW added", capacityFactor, addedMW)
+	}
+	loadMult, err := circuit.Solution.Get_LoadMult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadMult != 0.8 {
+		t.Errorf("expected the load multiplier to be restored to 0.8, got %g", loadMult)
+	}
+}
+
+func TestSolutionRunControlIteration(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new capacitor.c1 bus1=b2 phases=3 kv=12.47 kvar=200 states=[0]
+new capcontrol.cc1 capacitor=c1 element=line.l1 terminal=1 type=kvar onsetting=150 offsetting=-400 delay=1
+`)
+	circuit := &dss.ActiveCircuit
+	solution := &circuit.Solution
+	done := false
+	iterations := 0
+	for ; !done && iterations < 10; iterations++ {
+		var err error
+		if done, err = solution.RunControlIteration(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !done {
+		t.Fatal("expected the control actions to finish within 10 iterations")
+	}
+	// The load draws 300 kvar through the line, above the ON setting of the controller
+	if err := circuit.Capacitors.Set_Name("c1"); err != nil {
+		t.Fatal(err)
+	}
+	states, err := circuit.Capacitors.Get_States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0] != 1 {
+		t.Errorf("expected the capacitor to be switched on after %d iterations, got states %v", iterations, states)
+	}
+}
+
+func TestCktElementOpenCloseAll(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	activateElement(t, dss, "Line.l1")
+	cktelement := &dss.ActiveCircuit.ActiveCktElement
+	// Checks each phase of both terminals of the line
+	checkOpen := func(expected bool) {
+		t.Helper()
+		for term := int32(1); term <= 2; term++ {
+			for phs := int32(1); phs <= 3; phs++ {
+				open, err := cktelement.IsOpen(term, phs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if open != expected {
+					t.Errorf("expected IsOpen(%d, %d) to be %v", term, phs, expected)
+				}
+			}
+		}
+	}
+
+	if err := cktelement.OpenAll(); err != nil {
+		t.Fatal(err)
+	}
+	checkOpen(true)
+	if err := cktelement.CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+	checkOpen(false)
+}
+
+func TestMetersSectionReport(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 phases=3 length=2 units=km
+new load.ld2 bus1=b3 phases=3 kv=12.47 kw=200 kvar=50
+new fuse.f1 monitoredobj=line.l2 monitoredterm=1
+new energymeter.m1 element=line.l1 terminal=1
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	meters := &circuit.Meters
+	if err := meters.Set_Name("m1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := meters.DoReliabilityCalc(false); err != nil {
+		t.Fatal(err)
+	}
+	numSections, err := meters.NumSections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numSections < 2 {
+		t.Fatalf("expected the fuse to split the zone in sections, got %d", numSections)
+	}
+	for sectIdx := int32(1); sectIdx <= numSections; sectIdx++ {
+		info, err := meters.SectionReport(sectIdx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The section stays active, so the report must match the individual getters
+		expected := SectionInfo{SectionIndex: sectIdx}
+		if expected.SeqIdx, err = meters.SectSeqIdx(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.NumBranches, err = meters.NumSectionBranches(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.NumCustomers, err = meters.NumSectionCustomers(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.TotalCustomers, err = meters.SectTotalCust(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.OCPDeviceType, err = meters.OCPDeviceType(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.SumBranchFltRates, err = meters.SumBranchFltRates(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.AvgRepairTime, err = meters.AvgRepairTime(); err != nil {
+			t.Fatal(err)
+		}
+		if expected.FaultRateXRepairHrs, err = meters.FaultRateXRepairHrs(); err != nil {
+			t.Fatal(err)
+		}
+		if info != expected {
+			t.Errorf("section %d: expected %+v, got %+v", sectIdx, expected, info)
+		}
+		if info.NumBranches < 1 {
+			t.Errorf("section %d: expected at least one branch", sectIdx)
+		}
+	}
+}
+
+func TestCircuitSequenceVoltages(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new load.ld2 bus1=b2.1 phases=1 kv=7.2 kw=500 kvar=100
+new line.l2 bus1=b2.2 bus2=b3.2 phases=1 length=1 units=km
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := circuit.SequenceVoltages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := seq["b3"]; found || len(seq) != 2 {
+		t.Fatalf("expected only the 3-phase buses src and b2, got %v", seq)
+	}
+	for _, busName := range []string{"src", "b2"} {
+		if _, err = circuit.SetActiveBus(busName); err != nil {
+			t.Fatal(err)
+		}
+		// The engine reports the magnitudes only
+		expected, err := circuit.ActiveBus.SeqVoltages()
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, found := seq[busName]
+		if !found || len(expected) != 3 {
+			t.Fatalf("expected the sequence voltages of %s, got %v", busName, seq)
+		}
+		for i := range v {
+			if math.Abs(cmplx.Abs(v[i])-expected[i]) > 1e-6*expected[1] {
+				t.Errorf("%s: expected the magnitudes %v, got %v", busName, expected, v)
+				break
+			}
+		}
+	}
+	// The unbalanced load must show up as negative sequence voltage at its bus
+	if cmplx.Abs(seq["b2"][2]) < 1 {
+		t.Errorf("expected a negative sequence voltage at b2, got %v", seq["b2"])
+	}
+}
+
+func TestCircuitQuickSolve(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	circuit := &dss.ActiveCircuit
+	report, err := circuit.QuickSolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Converged {
+		t.Fatal("expected the solution to converge")
+	}
+	// TotalPower is negative when importing power from the source
+	if real(report.TotalPower) > -1000 || real(report.Losses) <= 0 {
+		t.Errorf("expected more than 1000 kW delivered and positive losses, got %v and %v", report.TotalPower, report.Losses)
+	}
+	// The voltage drops along the line, from the source to the load
+	if !strings.HasPrefix(report.MaxVoltageNode, "src.") || !strings.HasPrefix(report.MinVoltageNode, "b2.") {
+		t.Errorf("expected the highest voltage at src and the lowest at b2, got %s and %s", report.MaxVoltageNode, report.MinVoltageNode)
+	}
+	if report.MinVoltagePU >= report.MaxVoltagePU || report.MaxVoltagePU > 1.0001 {
+		t.Errorf("unexpected voltage range: %g to %g pu", report.MinVoltagePU, report.MaxVoltagePU)
+	}
+}
+
+func TestPDElementsFaultRates(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 phases=3 length=1 units=km
+`)
+	pdelements := &dss.ActiveCircuit.PDElements
+	err := pdelements.SetAllFaultRates(map[string]float64{"line.L1": 0.2, "Line.l2": 0.35, "Line.missing": 1})
+	if err == nil || !strings.Contains(err.Error(), "line.missing") {
+		t.Errorf("expected an error for the missing element, got %v", err)
+	}
+	rates, err := pdelements.AllFaultRates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lowered := make(map[string]float64, len(rates))
+	for name, rate := range rates {
+		lowered[strings.ToLower(name)] = rate
+	}
+	if len(lowered) != 2 || lowered["line.l1"] != 0.2 || lowered["line.l2"] != 0.35 {
+		t.Errorf("expected the fault rates to round-trip, got %v", rates)
+	}
+}
+
+func TestGetStringArrayError(t *testing.T) {
+	// Without a circuit, the calls fail; no data must be read
+	dss := newTestContext(t, "clear")
+	for what, get := range map[string]func() ([]string, error){
+		"AllBusNames":  dss.ActiveCircuit.AllBusNames,
+		"AllNodeNames": dss.ActiveCircuit.AllNodeNames,
+		"YNodeOrder":   dss.ActiveCircuit.YNodeOrder,
+	} {
+		names, err := get()
+		if err == nil {
+			t.Errorf("%s: expected an error without a circuit", what)
+		}
+		if names != nil {
+			t.Errorf("%s: expected a nil slice on errors, got %v", what, names)
+		}
+	}
+}
+
+func TestSolutionIncidenceGraph(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 phases=3 length=1 units=km
+`)
+	if err := dss.ActiveCircuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dss.Text.Set_Command("CalcIncMatrix_O"); err != nil {
+		t.Fatal(err)
+	}
+	graph, err := dss.ActiveCircuit.Solution.IncidenceGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.BusLevels) != len(graph.Buses) || len(graph.Adjacency) != len(graph.Buses) {
+		t.Fatalf("expected the levels and adjacency of %d buses, got %d and %d", len(graph.Buses), len(graph.BusLevels), len(graph.Adjacency))
+	}
+	busIndex := func(name string) int {
+		for i, bus := range graph.Buses {
+			if strings.EqualFold(bus, name) {
+				return i
+			}
+		}
+		t.Fatalf("bus %s not found in %v", name, graph.Buses)
+		return -1
+	}
+	src, b2, b3 := busIndex("src"), busIndex("b2"), busIndex("b3")
+	for _, expected := range []struct {
+		name     string
+		from, to int
+	}{{"Line.l1", src, b2}, {"Line.l2", b2, b3}} {
+		found := false
+		for _, edge := range graph.Edges {
+			if strings.EqualFold(edge.Name, expected.name) {
+				found = true
+				if !(edge.From == expected.from && edge.To == expected.to) && !(edge.From == expected.to && edge.To == expected.from) {
+					t.Errorf("expected %s to connect buses %d and %d, got %+v", expected.name, expected.from, expected.to, edge)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected an edge for %s, got %+v", expected.name, graph.Edges)
+		}
+	}
+	if len(graph.Adjacency[b2]) != 2 || len(graph.Adjacency[b3]) != 1 {
+		t.Errorf("expected b2 to have 2 neighbours and b3 one, got %v", graph.Adjacency)
+	}
+	if graph.BusLevels[src] >= graph.BusLevels[b3] {
+		t.Errorf("expected src to be at a lower level than b3, got %v", graph.BusLevels)
+	}
+}
+
+func TestGeneratorsOutputPower(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new generator.g1 bus1=b2 phases=3 kv=12.47 kw=400 kvar=100 model=1
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if err := circuit.Generators.Set_Name("g1"); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	power, err := circuit.Generators.OutputPower()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A constant power generator delivers its setpoint
+	if math.Abs(real(power)-400) > 1 || math.Abs(imag(power)-100) > 1 {
+		t.Errorf("expected an output of about 400+100i kVA, got %v", power)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestCktElementVoltageDrop(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	drop, err := circuit.ActiveCktElement.VoltageDrop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compare with the node voltages of the buses at both ends of the line
+	busVoltages := func(name string) []complex128 {
+		t.Helper()
+		if _, err := circuit.SetActiveBus(name); err != nil {
+			t.Fatal(err)
+		}
+		volts, err := circuit.ActiveBus.Voltages()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return volts
+	}
+	from, to := busVoltages("src"), busVoltages("b2")
+	if len(drop) != 3 || len(from) < 3 || len(to) < 3 {
+		t.Fatalf("expected 3 phases, got %v", drop)
+	}
+	for i := range drop {
+		if cmplx.Abs(drop[i]-(from[i]-to[i])) > 1e-6 {
+			t.Errorf("phase %d: expected a drop of %v, got %v", i+1, from[i]-to[i], drop[i])
+		}
+		if cmplx.Abs(drop[i]) <= 0 {
+			t.Errorf("phase %d: expected a non-zero drop", i+1)
+		}
+	}
+
+	activateElement(t, dss, "Load.ld1")
+	if _, err = circuit.ActiveCktElement.VoltageDrop(); err == nil {
+		t.Error("expected an error for a single-terminal element")
+	}
+}
+
+func TestSolutionStepMinutes(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+set mode=daily number=1
+`)
+	solution := &dss.ActiveCircuit.Solution
+	if err := solution.Set_dblHour(0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := solution.StepMinutes(15); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dblHour, err := solution.Get_dblHour()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour, err := solution.Get_Hour()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seconds, err := solution.Get_Seconds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stepSize, err := solution.Get_StepSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dblHour-1.25) > 1e-9 || hour != 1 || math.Abs(seconds-900) > 1e-6 || stepSize != 900 {
+		t.Errorf("expected 1.25 h (hour 1, 900 s) with a 900 s step, got %g h (hour %d, %g s) with a %g s step", dblHour, hour, seconds, stepSize)
+	}
+	if err = solution.SolveSnap(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCircuitActivateClass(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new line.l2 bus1=b2 bus2=b3 phases=3 length=1 units=km
+`)
+	circuit := &dss.ActiveCircuit
+	activeClass, err := circuit.ActivateClass("line")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activeClass != &circuit.ActiveClass {
+		t.Fatal("expected the ActiveClass interface")
+	}
+	name, err := activeClass.ActiveClassName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := activeClass.AllNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(name, "Line") || sortedLower(names) != "l1,l2" {
+		t.Errorf("expected the Line class with l1 and l2, got %s with %v", name, names)
+	}
+	if _, err = circuit.ActivateClass("NoSuchClass"); err == nil {
+		t.Error("expected an error for an unknown class")
+	}
+}
+
+func TestLoadsAssignDailyShapes(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new load.ld2 bus1=b2 phases=3 kv=12.47 kw=200 kvar=50
+new loadshape.residential npts=4 interval=6 mult=[0.4, 0.6, 0.8, 1]
+new loadshape.commercial npts=4 interval=6 mult=[0.3, 1, 0.9, 0.4]
+`)
+	loads := &dss.ActiveCircuit.Loads
+	err := loads.AssignDailyShapes(map[string]string{"LD1": "residential", "ld2": "commercial", "missing": "residential"})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error for the missing load, got %v", err)
+	}
+	for load, expected := range map[string]string{"ld1": "residential", "ld2": "commercial"} {
+		if err = loads.Set_Name(load); err != nil {
+			t.Fatal(err)
+		}
+		shape, err := loads.Get_daily()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.EqualFold(shape, expected) {
+			t.Errorf("expected %s to use the %s shape, got %q", load, expected, shape)
+		}
+	}
+}
+
+func TestCircuitVoltageSummary(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	vmin, vmax, mean, stddev, err := circuit.VoltageSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The circuit is balanced: three nodes at the source voltage and three at the load
+	// voltage, so the mean is halfway and the deviation is half the range
+	if vmin >= vmax || vmax > 1.0001 || vmin < 0.9 {
+		t.Fatalf("unexpected voltage range: %g to %g pu", vmin, vmax)
+	}
+	if math.Abs(mean-(vmin+vmax)/2) > 1e-6 || math.Abs(stddev-(vmax-vmin)/2) > 1e-6 {
+		t.Errorf("expected a mean of %g and a deviation of %g, got %g and %g", (vmin+vmax)/2, (vmax-vmin)/2, mean, stddev)
+	}
+
+	empty := newTestContext(t, "clear")
+	if _, _, _, _, err = empty.ActiveCircuit.VoltageSummary(); err == nil {
+		t.Error("expected an error without a circuit")
+	}
+}
+
+func TestTransformersRegControlName(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new transformer.reg1 phases=3 windings=2 buses=[b2, b3] kvs=[12.47, 12.47] kvas=[5000, 5000] xhl=0.1
+new regcontrol.rc1 transformer=reg1 winding=2 vreg=120 band=2 ptratio=60
+new transformer.t2 phases=3 windings=2 buses=[b3, b4] kvs=[12.47, 0.48] kvas=[500, 500]
+`)
+	circuit := &dss.ActiveCircuit
+	if err := circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	for transformer, expected := range map[string]string{"reg1": "rc1", "t2": ""} {
+		if err := circuit.Transformers.Set_Name(transformer); err != nil {
+			t.Fatal(err)
+		}
+		name, err := circuit.Transformers.RegControlName()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.EqualFold(name, expected) {
+			t.Errorf("expected the RegControl of %s to be %q, got %q", transformer, expected, name)
+		}
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestSolutionSolveNoControlSnapshot(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+new capacitor.c1 bus1=b2 phases=3 kv=12.47 kvar=200 states=[0]
+new capcontrol.cc1 capacitor=c1 element=line.l1 terminal=1 type=kvar onsetting=150 offsetting=-400 delay=1
+`)
+	circuit := &dss.ActiveCircuit
+	solution := &circuit.Solution
+	if err := solution.Set_ControlMode(ControlModes_Time); err != nil {
+		t.Fatal(err)
+	}
+	if err := solution.SolveNoControlSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	controlMode, err := solution.Get_ControlMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controlMode != ControlModes_Time {
+		t.Errorf("expected the control mode to be restored to Time, got %d", controlMode)
+	}
+	// Without controls, the capacitor must not have been switched on
+	if err = circuit.Capacitors.Set_Name("c1"); err != nil {
+		t.Fatal(err)
+	}
+	states, err := circuit.Capacitors.Get_States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0] != 0 {
+		t.Errorf("expected the capacitor to stay open, got states %v", states)
+	}
+}
+
+func TestVsourcesPowersAndEquivalentZsc(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript+`
+edit vsource.source mvasc3=100 mvasc1=90 x1r1=4 x0r0=3
+`)
+	circuit := &dss.ActiveCircuit
+	vsources := &circuit.Vsources
+	if err := vsources.Set_Name("source"); err != nil {
+		t.Fatal(err)
+	}
+	zsc, err := vsources.EquivalentZsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// |Z1| = kV^2 / MVAsc3, with X/R = 4
+	if math.Abs(cmplx.Abs(zsc)-12.47*12.47/100) > 1e-3 || math.Abs(imag(zsc)/real(zsc)-4) > 1e-3 {
+		t.Errorf("expected |Z1|=%g ohm with X/R=4, got %v", 12.47*12.47/100, zsc)
+	}
+
+	if err = circuit.Solution.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	activateElement(t, dss, "Line.l1")
+	powers, err := vsources.Powers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	totalPower, err := circuit.TotalPower()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(powers) < 3 {
+		t.Fatalf("expected the powers of 3 phases, got %v", powers)
+	}
+	// The source is the only supply, so its injection is the total power of the circuit
+	injected := powers[0] + powers[1] + powers[2]
+	if real(injected) >= 0 || cmplx.Abs(injected-totalPower) > 1e-6*cmplx.Abs(totalPower) {
+		t.Errorf("expected the source to inject the total power %v, got %v", totalPower, injected)
+	}
+	checkActiveElement(t, dss, "Line.l1")
+}
+
+func TestSettingsWithAllowDuplicates(t *testing.T) {
+	dss := newTestContext(t, testCircuitScript)
+	settings := &dss.ActiveCircuit.Settings
+	checkRestored := func() {
+		t.Helper()
+		allow, err := settings.Get_AllowDuplicates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allow {
+			t.Error("expected AllowDuplicates to be restored to false")
+		}
+	}
+
+	errCallback := errors.New("callback failed")
+	err := settings.WithAllowDuplicates(true, func() error {
+		allow, err := settings.Get_AllowDuplicates()
+		if err != nil {
+			return err
+		}
+		if !allow {
+			t.Error("expected AllowDuplicates to be enabled in the callback")
+		}
+		if err = dss.Text.Set_Command("new load.ld1 bus1=b2 phases=3 kv=12.47 kw=10"); err != nil {
+			return err
+		}
+		return errCallback
+	})
+	if err != errCallback {
+		t.Errorf("expected the error of the callback, got %v", err)
+	}
+	checkRestored()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		settings.WithAllowDuplicates(true, func() error {
+			panic("callback panicked")
+		})
+	}()
+	checkRestored()
+}